@@ -16,18 +16,23 @@ type ApiClient interface {
 }
 
 type HciApiClient struct {
-	apiURL     string
-	apiKey     string
-	httpClient *http.Client
+	apiURL      string
+	credentials CredentialProvider
+	httpClient  *http.Client
 }
 
 const API_KEY_HEADER = "MC-Api-Key"
 
 func NewApiClient(apiURL, apiKey string) ApiClient {
+	return NewApiClientWithCredentials(apiURL, NewStaticCredentialProvider(apiKey))
+}
+
+// Create an ApiClient that asks the CredentialProvider for the API key of every request
+func NewApiClientWithCredentials(apiURL string, credentials CredentialProvider) ApiClient {
 	return HciApiClient{
-		apiURL:     apiURL,
-		apiKey:     apiKey,
-		httpClient: &http.Client{},
+		apiURL:      apiURL,
+		credentials: credentials,
+		httpClient:  &http.Client{},
 	}
 }
 
@@ -36,9 +41,23 @@ func NewInsecureApiClient(apiURL, apiKey string) ApiClient {
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
 	return HciApiClient{
-		apiURL:     apiURL,
-		apiKey:     apiKey,
-		httpClient: &http.Client{Transport: tr},
+		apiURL:      apiURL,
+		credentials: NewStaticCredentialProvider(apiKey),
+		httpClient:  &http.Client{Transport: tr},
+	}
+}
+
+// Create an ApiClient that uses the provided http.Client to do the calls. Useful to customize TLS settings, proxies or timeouts.
+func NewApiClientWithHttpClient(apiURL, apiKey string, httpClient *http.Client) ApiClient {
+	return NewApiClientWithCredentialsAndHttpClient(apiURL, NewStaticCredentialProvider(apiKey), httpClient)
+}
+
+// Create an ApiClient using both a CredentialProvider and a custom http.Client
+func NewApiClientWithCredentialsAndHttpClient(apiURL string, credentials CredentialProvider, httpClient *http.Client) ApiClient {
+	return HciApiClient{
+		apiURL:      apiURL,
+		credentials: credentials,
+		httpClient:  httpClient,
 	}
 }
 
@@ -69,7 +88,11 @@ func (hciClient HciApiClient) Do(request HciRequest) (*HciResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add(API_KEY_HEADER, hciClient.apiKey)
+	apiKey, err := hciClient.credentials.GetApiKey()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(API_KEY_HEADER, apiKey)
 	req.Header.Add("Content-Type", "application/json")
 	resp, err := hciClient.httpClient.Do(req)
 	if err != nil {
@@ -79,8 +102,15 @@ func (hciClient HciApiClient) Do(request HciRequest) (*HciResponse, error) {
 	return NewHciResponse(resp)
 }
 
+// Get the API key currently returned by the CredentialProvider
 func (hciClient HciApiClient) GetApiKey() string {
-	return hciClient.apiKey
+	apiKey, _ := hciClient.credentials.GetApiKey()
+	return apiKey
+}
+
+// Get the CredentialProvider consulted for every request
+func (hciClient HciApiClient) GetCredentialProvider() CredentialProvider {
+	return hciClient.credentials
 }
 
 func (hciClient HciApiClient) GetApiURL() string {
@@ -29,7 +29,7 @@ func TestGetTaskReturnTaskIfSuccess(t *testing.T) {
 	}
 
 	httpClient := &http.Client{Transport: transport}
-	hciClient := HciApiClient{server.URL, "api-key", httpClient}
+	hciClient := HciApiClient{apiURL: server.URL, credentials: NewStaticCredentialProvider("api-key"), httpClient: httpClient}
 
 	expectedResp := HciResponse{
 		TaskId:     "test_task_id",
@@ -62,7 +62,7 @@ func TestGetTaskReturnErrorsIfErrorOccured(t *testing.T) {
 	}
 
 	httpClient := &http.Client{Transport: transport}
-	hciClient := HciApiClient{server.URL, "api-key", httpClient}
+	hciClient := HciApiClient{apiURL: server.URL, credentials: NewStaticCredentialProvider("api-key"), httpClient: httpClient}
 
 	expectedResp := HciResponse{
 		Errors:     []HciError{{ErrorCode: "FOO_ERROR", Message: "message1"}, {ErrorCode: "BAR_ERROR", Message: "message2"}},
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Minimum delay between two checks of the credential file for changes
+const DEFAULT_CREDENTIAL_FILE_CHECK_INTERVAL = 5 * time.Second
+
+// Provides the API key of a request. It is consulted for every request, so keys can be rotated
+// without rebuilding the ApiClient or the services using it.
+type CredentialProvider interface {
+	GetApiKey() (string, error)
+}
+
+// A CredentialProvider holding a key in memory. The key can be replaced at any time with SetApiKey.
+type StaticCredentialProvider struct {
+	mutex  sync.RWMutex
+	apiKey string
+}
+
+func NewStaticCredentialProvider(apiKey string) *StaticCredentialProvider {
+	return &StaticCredentialProvider{apiKey: apiKey}
+}
+
+func (provider *StaticCredentialProvider) GetApiKey() (string, error) {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+	return provider.apiKey, nil
+}
+
+// Replace the key used by all subsequent requests
+func (provider *StaticCredentialProvider) SetApiKey(apiKey string) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	provider.apiKey = apiKey
+}
+
+// A CredentialProvider reading the key from a file. The file is checked for changes at most once
+// per check interval and reloaded when its modification time or size changes. If the file cannot be
+// read anymore, the last key read is kept.
+type FileCredentialProvider struct {
+	mutex         sync.Mutex
+	path          string
+	checkInterval time.Duration
+	apiKey        string
+	modTime       time.Time
+	size          int64
+	lastCheck     time.Time
+	now           func() time.Time
+}
+
+// Create a FileCredentialProvider checking the file every DEFAULT_CREDENTIAL_FILE_CHECK_INTERVAL.
+// Returns an error if the file cannot be read.
+func NewFileCredentialProvider(path string) (*FileCredentialProvider, error) {
+	return NewFileCredentialProviderWithInterval(path, DEFAULT_CREDENTIAL_FILE_CHECK_INTERVAL)
+}
+
+// Create a FileCredentialProvider with a custom check interval
+func NewFileCredentialProviderWithInterval(path string, checkInterval time.Duration) (*FileCredentialProvider, error) {
+	provider := &FileCredentialProvider{
+		path:          path,
+		checkInterval: checkInterval,
+		now:           time.Now,
+	}
+	if err := provider.reload(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func (provider *FileCredentialProvider) GetApiKey() (string, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+	if provider.now().Sub(provider.lastCheck) >= provider.checkInterval {
+		provider.reload()
+	}
+	return provider.apiKey, nil
+}
+
+// Read the file again if it changed since the last read. Must be called with the mutex held.
+func (provider *FileCredentialProvider) reload() error {
+	provider.lastCheck = provider.now()
+	info, err := os.Stat(provider.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(provider.modTime) && info.Size() == provider.size && provider.apiKey != "" {
+		return nil
+	}
+	content, err := ioutil.ReadFile(provider.path)
+	if err != nil {
+		return err
+	}
+	apiKey := strings.TrimSpace(string(content))
+	if apiKey == "" {
+		return fmt.Errorf("Credential file %s is empty", provider.path)
+	}
+	provider.apiKey = apiKey
+	provider.modTime = info.ModTime()
+	provider.size = info.Size()
+	return nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoUsesTheCurrentKeyOfTheCredentialProvider(t *testing.T) {
+	//given
+	receivedKeys := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedKeys = append(receivedKeys, r.Header.Get(API_KEY_HEADER))
+		w.WriteHeader(200)
+		fmt.Fprintln(w, `{"data": {}}`)
+	}))
+	defer server.Close()
+
+	credentials := NewStaticCredentialProvider("first-key")
+	hciClient := NewApiClientWithCredentials(server.URL, credentials)
+
+	//when
+	hciClient.Do(HciRequest{Method: GET, Endpoint: "/fooo"})
+	credentials.SetApiKey("second-key")
+	hciClient.Do(HciRequest{Method: GET, Endpoint: "/fooo"})
+
+	//then
+	assert.Equal(t, []string{"first-key", "second-key"}, receivedKeys)
+	assert.Equal(t, "second-key", hciClient.GetApiKey())
+}
+
+func TestFileCredentialProviderReloadsChangedFileAfterCheckInterval(t *testing.T) {
+	//given
+	dir, _ := ioutil.TempDir("", "hci-credentials")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api-key")
+	ioutil.WriteFile(path, []byte("first-key\n"), 0600)
+
+	provider, err := NewFileCredentialProviderWithInterval(path, time.Minute)
+	if !assert.NoError(t, err) {
+		return
+	}
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+	provider.lastCheck = now
+
+	ioutil.WriteFile(path, []byte("rotated-key-with-another-size\n"), 0600)
+
+	//when
+	beforeInterval, _ := provider.GetApiKey()
+	now = now.Add(time.Minute)
+	afterInterval, _ := provider.GetApiKey()
+
+	//then
+	assert.Equal(t, "first-key", beforeInterval)
+	assert.Equal(t, "rotated-key-with-another-size", afterInterval)
+}
+
+func TestFileCredentialProviderKeepsLastKeyIfFileDisappears(t *testing.T) {
+	//given
+	dir, _ := ioutil.TempDir("", "hci-credentials")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api-key")
+	ioutil.WriteFile(path, []byte("first-key"), 0600)
+
+	provider, _ := NewFileCredentialProviderWithInterval(path, 0)
+	os.Remove(path)
+
+	//when
+	apiKey, err := provider.GetApiKey()
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "first-key", apiKey)
+}
+
+func TestNewFileCredentialProviderReturnsErrorIfFileIsMissing(t *testing.T) {
+	//when
+	_, err := NewFileCredentialProvider("/does/not/exist")
+
+	//then
+	assert.Error(t, err)
+}
@@ -1,6 +1,8 @@
 package hci
 
 import (
+	"fmt"
+
 	"github.com/hypertec-cloud/go-hci/api"
 	"github.com/hypertec-cloud/go-hci/configuration"
 	"github.com/hypertec-cloud/go-hci/services"
@@ -18,6 +20,8 @@ type HciClient struct {
 	Users              configuration.UserService
 	ServiceConnections configuration.ServiceConnectionService
 	Organizations      configuration.OrganizationService
+	defaultServiceCode string
+	defaultEnvironment string
 }
 
 // Create a HciClient with the default URL
@@ -37,6 +41,12 @@ func NewInsecureHciClientWithURL(apiURL string, apiKey string) *HciClient {
 	return NewHciClientWithApiClient(apiClient)
 }
 
+// Create a HciClient with a custom URL that asks the CredentialProvider for the API key of every request
+func NewHciClientWithCredentials(apiURL string, credentials api.CredentialProvider) *HciClient {
+	apiClient := api.NewApiClientWithCredentials(apiURL, credentials)
+	return NewHciClientWithApiClient(apiClient)
+}
+
 func NewHciClientWithApiClient(apiClient api.ApiClient) *HciClient {
 	hciClient := HciClient{
 		apiClient:          apiClient,
@@ -66,6 +76,20 @@ func (c HciClient) GetApiKey() string {
 	return c.apiClient.GetApiKey()
 }
 
+// Replace the API key used by the client and all the Resources created from it.
+// Only supported when the key is held by an api.StaticCredentialProvider, which is the case for clients created with an API key string.
+func (c HciClient) RotateApiKey(apiKey string) error {
+	if withCredentials, ok := c.apiClient.(interface {
+		GetCredentialProvider() api.CredentialProvider
+	}); ok {
+		if provider, ok := withCredentials.GetCredentialProvider().(*api.StaticCredentialProvider); ok {
+			provider.SetApiKey(apiKey)
+			return nil
+		}
+	}
+	return fmt.Errorf("API key cannot be rotated: the client does not use a static credential provider")
+}
+
 // Get the API Client used by all the services
 func (c HciClient) GetApiClient() api.ApiClient {
 	return c.apiClient
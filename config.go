@@ -0,0 +1,278 @@
+package hci
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/services/hci"
+)
+
+// Environment variables read by LoadClientConfig
+const (
+	API_KEY_ENV_VAR      = "HCI_API_KEY"
+	API_KEY_FILE_ENV_VAR = "HCI_API_KEY_FILE"
+	API_URL_ENV_VAR      = "HCI_API_URL"
+	PROFILE_ENV_VAR      = "HCI_PROFILE"
+	CONFIG_FILE_ENV_VAR  = "HCI_CONFIG_FILE"
+	SERVICE_CODE_ENV_VAR = "HCI_SERVICE_CODE"
+	ENVIRONMENT_ENV_VAR  = "HCI_ENVIRONMENT"
+	INSECURE_ENV_VAR     = "HCI_INSECURE"
+	CA_BUNDLE_ENV_VAR    = "HCI_CA_BUNDLE"
+)
+
+// Keys of a profile section in the config file
+const (
+	API_URL_CONFIG_KEY      = "api_url"
+	API_KEY_CONFIG_KEY      = "api_key"
+	API_KEY_FILE_CONFIG_KEY = "api_key_file"
+	SERVICE_CODE_CONFIG_KEY = "service_code"
+	ENVIRONMENT_CONFIG_KEY  = "environment"
+	INSECURE_CONFIG_KEY     = "insecure"
+	CA_BUNDLE_CONFIG_KEY    = "ca_bundle"
+)
+
+const (
+	DEFAULT_PROFILE     = "default"
+	DEFAULT_CONFIG_FILE = ".hci/config"
+)
+
+// The settings used to build a HciClient
+type ClientConfig struct {
+	ApiURL string
+	ApiKey string
+	// Path of a file holding the API key. The file is watched, so the key can be rotated while the client is in use.
+	// Ignored when ApiKey is set. LoadClientConfig only keeps the key or key file of the source with the highest precedence.
+	ApiKeyFile  string
+	ServiceCode string
+	Environment string
+	// Accept any certificate presented by the server
+	Insecure bool
+	// Path of a PEM file with the certificate authorities to trust in addition to the system ones
+	CABundle string
+}
+
+// Controls where LoadClientConfig looks for settings
+type ConfigOptions struct {
+	// Profile to read from the config file. Defaults to HCI_PROFILE, then to "default".
+	Profile string
+	// Path of the config file. Defaults to HCI_CONFIG_FILE, then to ~/.hci/config.
+	ConfigFile string
+	// Explicit settings, applied last. Only non-empty values override the other sources.
+	Overrides ClientConfig
+	// Apply Overrides.Insecure even when false, to turn off insecure=true from the profile or the environment
+	OverrideInsecure bool
+}
+
+// Load a ClientConfig from, in increasing order of precedence: the defaults, the profile in the config file,
+// the HCI_* environment variables and the explicit overrides.
+//
+// The config file uses one section per profile:
+//
+//	[default]
+//	api_url = https://hypertec.cloud/api/v1/
+//	api_key = my-api-key
+//	api_key_file = /run/secrets/hci-api-key
+//	service_code = compute-qc
+//	environment = dev
+//	insecure = false
+//	ca_bundle = /etc/ssl/my-ca.pem
+//
+// A missing default config file is ignored, but a config file or profile that was explicitly requested must exist.
+func LoadClientConfig(options ConfigOptions) (*ClientConfig, error) {
+	config := ClientConfig{ApiURL: DEFAULT_API_URL}
+
+	profile, profileRequested := firstNonEmpty(options.Profile, os.Getenv(PROFILE_ENV_VAR)), true
+	if profile == "" {
+		profile, profileRequested = DEFAULT_PROFILE, false
+	}
+	configFile, fileRequested := firstNonEmpty(options.ConfigFile, os.Getenv(CONFIG_FILE_ENV_VAR)), true
+	if configFile == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			configFile = filepath.Join(home, DEFAULT_CONFIG_FILE)
+		}
+		fileRequested = false
+	}
+
+	profiles, err := readConfigFile(configFile)
+	if err != nil {
+		if fileRequested || !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if values, ok := profiles[profile]; ok {
+		if err := config.apply(values); err != nil {
+			return nil, fmt.Errorf("Invalid profile %s in %s: %s", profile, configFile, err)
+		}
+	} else if profileRequested {
+		return nil, fmt.Errorf("Profile %s not found in %s", profile, configFile)
+	}
+
+	if err := config.apply(map[string]string{
+		API_URL_CONFIG_KEY:      os.Getenv(API_URL_ENV_VAR),
+		API_KEY_CONFIG_KEY:      os.Getenv(API_KEY_ENV_VAR),
+		API_KEY_FILE_CONFIG_KEY: os.Getenv(API_KEY_FILE_ENV_VAR),
+		SERVICE_CODE_CONFIG_KEY: os.Getenv(SERVICE_CODE_ENV_VAR),
+		ENVIRONMENT_CONFIG_KEY:  os.Getenv(ENVIRONMENT_ENV_VAR),
+		INSECURE_CONFIG_KEY:     os.Getenv(INSECURE_ENV_VAR),
+		CA_BUNDLE_CONFIG_KEY:    os.Getenv(CA_BUNDLE_ENV_VAR),
+	}); err != nil {
+		return nil, fmt.Errorf("Invalid environment variable: %s", err)
+	}
+
+	overrides := options.Overrides
+	config.ApiURL = firstNonEmpty(overrides.ApiURL, config.ApiURL)
+	config.applyApiKey(overrides.ApiKey, overrides.ApiKeyFile)
+	config.ServiceCode = firstNonEmpty(overrides.ServiceCode, config.ServiceCode)
+	config.Environment = firstNonEmpty(overrides.Environment, config.Environment)
+	config.CABundle = firstNonEmpty(overrides.CABundle, config.CABundle)
+	if overrides.Insecure || options.OverrideInsecure {
+		config.Insecure = overrides.Insecure
+	}
+	return &config, nil
+}
+
+// Create a HciClient with the settings found by LoadClientConfig
+func NewHciClientFromConfigChain(options ConfigOptions) (*HciClient, error) {
+	config, err := LoadClientConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	return NewHciClientFromConfig(*config)
+}
+
+// Create a HciClient from a ClientConfig
+func NewHciClientFromConfig(config ClientConfig) (*HciClient, error) {
+	var credentials api.CredentialProvider
+	if config.ApiKey != "" {
+		credentials = api.NewStaticCredentialProvider(config.ApiKey)
+	} else if config.ApiKeyFile != "" {
+		fileCredentials, err := api.NewFileCredentialProvider(config.ApiKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		credentials = fileCredentials
+	} else {
+		return nil, fmt.Errorf("No API key configured. Set %s, %s or %s in the config file", API_KEY_ENV_VAR, API_KEY_FILE_ENV_VAR, API_KEY_CONFIG_KEY)
+	}
+	httpClient, err := config.buildHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	hciClient := NewHciClientWithApiClient(api.NewApiClientWithCredentialsAndHttpClient(firstNonEmpty(config.ApiURL, DEFAULT_API_URL), credentials, httpClient))
+	hciClient.defaultServiceCode = config.ServiceCode
+	hciClient.defaultEnvironment = config.Environment
+	return hciClient, nil
+}
+
+// Get the verified Resources of the default service code and environment of the client configuration
+func (c HciClient) GetDefaultResources() (hci.Resources, error) {
+	if c.defaultServiceCode == "" || c.defaultEnvironment == "" {
+		return hci.Resources{}, fmt.Errorf("No default service code and environment configured. Set %s and %s", SERVICE_CODE_ENV_VAR, ENVIRONMENT_ENV_VAR)
+	}
+	return c.GetVerifiedResources(c.defaultServiceCode, c.defaultEnvironment)
+}
+
+func (config ClientConfig) buildHttpClient() (*http.Client, error) {
+	if !config.Insecure && config.CABundle == "" {
+		return &http.Client{}, nil
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.Insecure}
+	if config.CABundle != "" {
+		pem, err := ioutil.ReadFile(config.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No certificate found in CA bundle %s", config.CABundle)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	return &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}}, nil
+}
+
+// Set the fields of the config for the non-empty values
+func (config *ClientConfig) apply(values map[string]string) error {
+	config.ApiURL = firstNonEmpty(values[API_URL_CONFIG_KEY], config.ApiURL)
+	config.applyApiKey(values[API_KEY_CONFIG_KEY], values[API_KEY_FILE_CONFIG_KEY])
+	config.ServiceCode = firstNonEmpty(values[SERVICE_CODE_CONFIG_KEY], config.ServiceCode)
+	config.Environment = firstNonEmpty(values[ENVIRONMENT_CONFIG_KEY], config.Environment)
+	config.CABundle = firstNonEmpty(values[CA_BUNDLE_CONFIG_KEY], config.CABundle)
+	if insecure := values[INSECURE_CONFIG_KEY]; insecure != "" {
+		value, err := strconv.ParseBool(insecure)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, got %s", INSECURE_CONFIG_KEY, insecure)
+		}
+		config.Insecure = value
+	}
+	return nil
+}
+
+// The API key and the API key file are a single setting: a source setting either one replaces both
+func (config *ClientConfig) applyApiKey(apiKey string, apiKeyFile string) {
+	if apiKey != "" || apiKeyFile != "" {
+		config.ApiKey = apiKey
+		config.ApiKeyFile = apiKeyFile
+	}
+}
+
+// Parse an INI style config file into a map of profile name to key/value pairs
+func readConfigFile(path string) (map[string]map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	if path == "" {
+		return profiles, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return profiles, err
+	}
+	defer file.Close()
+
+	var current map[string]string
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = map[string]string{}
+			}
+			current = profiles[name]
+			continue
+		}
+		separator := strings.Index(line, "=")
+		if separator < 0 || current == nil {
+			return nil, fmt.Errorf("Invalid line %d in config file %s", lineNumber, path)
+		}
+		current[strings.TrimSpace(line[:separator])] = strings.TrimSpace(line[separator+1:])
+	}
+	return profiles, scanner.Err()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
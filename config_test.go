@@ -0,0 +1,224 @@
+package hci
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const TEST_CONFIG_FILE = `# hci profiles
+[default]
+api_url = https://default.example.com/api/v1/
+api_key = default-key
+service_code = compute-qc
+environment = dev
+
+[staging]
+api_url = https://staging.example.com/api/v1/
+api_key = staging-key
+insecure = true
+`
+
+func writeTestConfigFile(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "hci-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+// Clear all the HCI_* environment variables then set the values. Returns a function restoring the original environment.
+func setTestEnv(values map[string]string) func() {
+	original := map[string]string{}
+	for _, entry := range os.Environ() {
+		if strings.HasPrefix(entry, "HCI_") {
+			keyValue := strings.SplitN(entry, "=", 2)
+			original[keyValue[0]] = keyValue[1]
+			os.Unsetenv(keyValue[0])
+		}
+	}
+	for key, value := range values {
+		os.Setenv(key, value)
+	}
+	return func() {
+		for key := range values {
+			os.Unsetenv(key)
+		}
+		for key, value := range original {
+			os.Setenv(key, value)
+		}
+	}
+}
+
+func TestLoadClientConfigReadsDefaultProfile(t *testing.T) {
+	defer setTestEnv(nil)()
+	//given
+	path, cleanup := writeTestConfigFile(t, TEST_CONFIG_FILE)
+	defer cleanup()
+
+	//when
+	config, err := LoadClientConfig(ConfigOptions{ConfigFile: path})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, ClientConfig{
+			ApiURL:      "https://default.example.com/api/v1/",
+			ApiKey:      "default-key",
+			ServiceCode: "compute-qc",
+			Environment: "dev",
+		}, *config)
+	}
+}
+
+func TestLoadClientConfigAppliesEnvironmentVariablesThenOverrides(t *testing.T) {
+	//given
+	path, cleanup := writeTestConfigFile(t, TEST_CONFIG_FILE)
+	defer cleanup()
+	defer setTestEnv(map[string]string{
+		PROFILE_ENV_VAR:     "staging",
+		API_KEY_ENV_VAR:     "env-key",
+		ENVIRONMENT_ENV_VAR: "env-environment",
+	})()
+
+	//when
+	config, err := LoadClientConfig(ConfigOptions{
+		ConfigFile: path,
+		Overrides:  ClientConfig{Environment: "override-environment"},
+	})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, ClientConfig{
+			ApiURL:      "https://staging.example.com/api/v1/",
+			ApiKey:      "env-key",
+			Environment: "override-environment",
+			Insecure:    true,
+		}, *config)
+	}
+}
+
+func TestLoadClientConfigOverrideCanTurnOffInsecure(t *testing.T) {
+	defer setTestEnv(map[string]string{INSECURE_ENV_VAR: "true"})()
+	//given
+	path, cleanup := writeTestConfigFile(t, TEST_CONFIG_FILE)
+	defer cleanup()
+
+	//when
+	config, err := LoadClientConfig(ConfigOptions{
+		ConfigFile:       path,
+		Profile:          "staging",
+		Overrides:        ClientConfig{Insecure: false},
+		OverrideInsecure: true,
+	})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.False(t, config.Insecure)
+	}
+}
+
+func TestLoadClientConfigApiKeyFileFromEnvironmentReplacesProfileApiKey(t *testing.T) {
+	defer setTestEnv(map[string]string{API_KEY_FILE_ENV_VAR: "/run/secrets/hci-api-key"})()
+	//given
+	path, cleanup := writeTestConfigFile(t, TEST_CONFIG_FILE)
+	defer cleanup()
+
+	//when
+	config, err := LoadClientConfig(ConfigOptions{ConfigFile: path})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "", config.ApiKey)
+		assert.Equal(t, "/run/secrets/hci-api-key", config.ApiKeyFile)
+	}
+}
+
+func TestLoadClientConfigApiKeyOverrideReplacesApiKeyFile(t *testing.T) {
+	defer setTestEnv(map[string]string{API_KEY_FILE_ENV_VAR: "/run/secrets/hci-api-key"})()
+	//given
+	path, cleanup := writeTestConfigFile(t, TEST_CONFIG_FILE)
+	defer cleanup()
+
+	//when
+	config, err := LoadClientConfig(ConfigOptions{ConfigFile: path, Overrides: ClientConfig{ApiKey: "override-key"}})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "override-key", config.ApiKey)
+		assert.Equal(t, "", config.ApiKeyFile)
+	}
+}
+
+func TestLoadClientConfigReturnsErrorIfRequestedProfileIsMissing(t *testing.T) {
+	defer setTestEnv(nil)()
+	//given
+	path, cleanup := writeTestConfigFile(t, TEST_CONFIG_FILE)
+	defer cleanup()
+
+	//when
+	_, err := LoadClientConfig(ConfigOptions{ConfigFile: path, Profile: "unknown"})
+
+	//then
+	assert.Error(t, err)
+}
+
+func TestLoadClientConfigReturnsErrorIfRequestedFileIsMissing(t *testing.T) {
+	defer setTestEnv(nil)()
+	//when
+	_, err := LoadClientConfig(ConfigOptions{ConfigFile: "/does/not/exist"})
+
+	//then
+	assert.Error(t, err)
+}
+
+func TestLoadClientConfigReturnsErrorIfInsecureIsNotABoolean(t *testing.T) {
+	defer setTestEnv(nil)()
+	//given
+	path, cleanup := writeTestConfigFile(t, "[default]\ninsecure = maybe\n")
+	defer cleanup()
+
+	//when
+	_, err := LoadClientConfig(ConfigOptions{ConfigFile: path})
+
+	//then
+	assert.Error(t, err)
+}
+
+func TestNewHciClientFromConfigUsesConfiguredUrlAndKey(t *testing.T) {
+	//when
+	client, err := NewHciClientFromConfig(ClientConfig{ApiURL: "https://example.com/api", ApiKey: "some-key", Insecure: true})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://example.com/api", client.GetApiURL())
+		assert.Equal(t, "some-key", client.GetApiKey())
+	}
+}
+
+func TestNewHciClientFromConfigReturnsErrorWithoutApiKey(t *testing.T) {
+	//when
+	_, err := NewHciClientFromConfig(ClientConfig{ApiURL: "https://example.com/api"})
+
+	//then
+	assert.Error(t, err)
+}
+
+func TestRotateApiKeyUpdatesClientCreatedFromConfig(t *testing.T) {
+	//given
+	client, _ := NewHciClientFromConfig(ClientConfig{ApiKey: "first-key"})
+
+	//when
+	err := client.RotateApiKey("second-key")
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "second-key", client.GetApiKey())
+}
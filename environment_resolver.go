@@ -0,0 +1,110 @@
+package hci
+
+import (
+	"strings"
+
+	"github.com/hypertec-cloud/go-hci/configuration"
+	"github.com/hypertec-cloud/go-hci/services/hci"
+)
+
+// Returned when no service connection matches the requested service code
+type ServiceConnectionNotFoundError struct {
+	ServiceCode string
+}
+
+func (e ServiceConnectionNotFoundError) Error() string {
+	return "No service connection found with service code " + e.ServiceCode
+}
+
+// Returned when no environment matches the requested name or id in a service connection
+type EnvironmentNotFoundError struct {
+	ServiceCode         string
+	EnvironmentNameOrId string
+}
+
+func (e EnvironmentNotFoundError) Error() string {
+	return "No environment found with name or id " + e.EnvironmentNameOrId + " in service connection " + e.ServiceCode
+}
+
+// Returned when several environments of a service connection match the requested name
+type AmbiguousEnvironmentError struct {
+	ServiceCode         string
+	EnvironmentNameOrId string
+	EnvironmentIds      []string
+}
+
+func (e AmbiguousEnvironmentError) Error() string {
+	return "Several environments match name " + e.EnvironmentNameOrId + " in service connection " + e.ServiceCode +
+		" (" + strings.Join(e.EnvironmentIds, ", ") + "), use the environment id instead"
+}
+
+// Find the environment with the specified name or id in the service connection with the specified serviceCode.
+// Ids are matched first so that a previously resolved environment is still found after it has been renamed.
+// Names are then matched exactly, and finally ignoring case. An error is returned if several environments match a name.
+func (c HciClient) ResolveEnvironment(serviceCode string, environmentNameOrId string) (*configuration.Environment, error) {
+	if _, err := c.resolveServiceConnection(serviceCode); err != nil {
+		return nil, err
+	}
+	environments, err := c.Environments.List()
+	if err != nil {
+		return nil, err
+	}
+	candidates := []configuration.Environment{}
+	for _, environment := range environments {
+		if environment.ServiceConnection.ServiceCode == serviceCode {
+			candidates = append(candidates, environment)
+		}
+	}
+	for _, environment := range candidates {
+		if environment.Id == environmentNameOrId {
+			return &environment, nil
+		}
+	}
+	matchers := []func(name string) bool{
+		func(name string) bool { return name == environmentNameOrId },
+		func(name string) bool { return strings.EqualFold(name, environmentNameOrId) },
+	}
+	for _, matches := range matchers {
+		matching := []configuration.Environment{}
+		for _, environment := range candidates {
+			if matches(environment.Name) {
+				matching = append(matching, environment)
+			}
+		}
+		if len(matching) == 1 {
+			return &matching[0], nil
+		}
+		if len(matching) > 1 {
+			ids := []string{}
+			for _, environment := range matching {
+				ids = append(ids, environment.Id)
+			}
+			return nil, AmbiguousEnvironmentError{ServiceCode: serviceCode, EnvironmentNameOrId: environmentNameOrId, EnvironmentIds: ids}
+		}
+	}
+	return nil, EnvironmentNotFoundError{ServiceCode: serviceCode, EnvironmentNameOrId: environmentNameOrId}
+}
+
+// Get the hci Resources for the environment with the specified name or id, after verifying that
+// both the service connection and the environment exist. The returned Resources are bound to the
+// current name of the environment.
+func (c HciClient) GetVerifiedResources(serviceCode string, environmentNameOrId string) (hci.Resources, error) {
+	environment, err := c.ResolveEnvironment(serviceCode, environmentNameOrId)
+	if err != nil {
+		return hci.Resources{}, err
+	}
+	return hci.NewResources(c.apiClient, serviceCode, environment.Name), nil
+}
+
+func (c HciClient) resolveServiceConnection(serviceCode string) (*configuration.ServiceConnection, error) {
+	serviceConnections, err := c.ServiceConnections.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, serviceConnection := range serviceConnections {
+		if serviceConnection.ServiceCode == serviceCode {
+			return &serviceConnection, nil
+		}
+	}
+	return nil, ServiceConnectionNotFoundError{ServiceCode: serviceCode}
+}
@@ -0,0 +1,160 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/hypertec-cloud/go-hci/configuration"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEnvironmentService struct {
+	configuration.EnvironmentService
+	environments []configuration.Environment
+	err          error
+}
+
+func (s fakeEnvironmentService) List() ([]configuration.Environment, error) {
+	return s.environments, s.err
+}
+
+type fakeServiceConnectionService struct {
+	configuration.ServiceConnectionService
+	serviceConnections []configuration.ServiceConnection
+}
+
+func (s fakeServiceConnectionService) List() ([]configuration.ServiceConnection, error) {
+	return s.serviceConnections, nil
+}
+
+func buildTestEnvironment(id string, name string, serviceCode string) configuration.Environment {
+	return configuration.Environment{
+		Id:                id,
+		Name:              name,
+		ServiceConnection: configuration.ServiceConnection{ServiceCode: serviceCode},
+	}
+}
+
+func buildTestHciClient(environments []configuration.Environment) *HciClient {
+	client := NewHciClientWithURL("http://localhost", "api-key")
+	client.Environments = fakeEnvironmentService{environments: environments}
+	client.ServiceConnections = fakeServiceConnectionService{serviceConnections: []configuration.ServiceConnection{
+		{Id: "sc1", ServiceCode: "compute-qc"},
+		{Id: "sc2", ServiceCode: "compute-on"},
+	}}
+	return client
+}
+
+func TestResolveEnvironmentByName(t *testing.T) {
+	//given
+	client := buildTestHciClient([]configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-on"),
+		buildTestEnvironment("env2", "dev", "compute-qc"),
+	})
+
+	//when
+	environment, err := client.ResolveEnvironment("compute-qc", "dev")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "env2", environment.Id)
+	}
+}
+
+func TestResolveEnvironmentByIdReturnsCurrentNameAfterRename(t *testing.T) {
+	//given
+	client := buildTestHciClient([]configuration.Environment{
+		buildTestEnvironment("env1", "renamed-dev", "compute-qc"),
+	})
+
+	//when
+	resources, err := client.GetVerifiedResources("compute-qc", "env1")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "renamed-dev", resources.GetEnvironmentName())
+		assert.Equal(t, "compute-qc", resources.GetServiceCode())
+	}
+}
+
+func TestResolveEnvironmentIgnoresCaseAsLastResort(t *testing.T) {
+	//given
+	client := buildTestHciClient([]configuration.Environment{
+		buildTestEnvironment("env1", "Prod", "compute-qc"),
+	})
+
+	//when
+	environment, err := client.ResolveEnvironment("compute-qc", "prod")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Prod", environment.Name)
+	}
+}
+
+func TestResolveEnvironmentReturnsErrorIfNameIsAmbiguous(t *testing.T) {
+	//given
+	client := buildTestHciClient([]configuration.Environment{
+		buildTestEnvironment("env1", "Prod", "compute-qc"),
+		buildTestEnvironment("env2", "PROD", "compute-qc"),
+	})
+
+	//when
+	_, err := client.ResolveEnvironment("compute-qc", "prod")
+
+	//then
+	assert.Equal(t, AmbiguousEnvironmentError{ServiceCode: "compute-qc", EnvironmentNameOrId: "prod", EnvironmentIds: []string{"env1", "env2"}}, err)
+}
+
+func TestResolveEnvironmentPrefersSingleExactNameMatch(t *testing.T) {
+	//given
+	client := buildTestHciClient([]configuration.Environment{
+		buildTestEnvironment("env1", "Prod", "compute-qc"),
+		buildTestEnvironment("env2", "prod", "compute-qc"),
+	})
+
+	//when
+	environment, err := client.ResolveEnvironment("compute-qc", "prod")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "env2", environment.Id)
+	}
+}
+
+func TestResolveEnvironmentReturnsErrorIfEnvironmentNotFound(t *testing.T) {
+	//given
+	client := buildTestHciClient([]configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-on"),
+	})
+
+	//when
+	_, err := client.GetVerifiedResources("compute-qc", "dev")
+
+	//then
+	assert.Equal(t, EnvironmentNotFoundError{ServiceCode: "compute-qc", EnvironmentNameOrId: "dev"}, err)
+}
+
+func TestResolveEnvironmentReturnsErrorIfServiceConnectionNotFound(t *testing.T) {
+	//given
+	client := buildTestHciClient([]configuration.Environment{})
+
+	//when
+	_, err := client.ResolveEnvironment("unknown", "dev")
+
+	//then
+	assert.Equal(t, ServiceConnectionNotFoundError{ServiceCode: "unknown"}, err)
+}
+
+func TestResolveEnvironmentReturnsListError(t *testing.T) {
+	//given
+	client := buildTestHciClient(nil)
+	mockError := mocks.MockError{Message: "some_list_error"}
+	client.Environments = fakeEnvironmentService{err: mockError}
+
+	//when
+	_, err := client.ResolveEnvironment("compute-qc", "dev")
+
+	//then
+	assert.Equal(t, mockError, err)
+}
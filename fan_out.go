@@ -0,0 +1,189 @@
+package hci
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/hypertec-cloud/go-hci/configuration"
+	"github.com/hypertec-cloud/go-hci/services/hci"
+)
+
+// Maximum number of environments queried at the same time by FanOut
+const DEFAULT_FAN_OUT_CONCURRENCY = 8
+
+// Selects the environments a fan-out query runs against. Empty fields match every environment.
+type EnvironmentFilter struct {
+	ServiceCodes     []string
+	EnvironmentNames []string
+	Match            func(environment configuration.Environment) bool
+}
+
+// A query executed once per environment. The returned value is stored in the EnvironmentResult.
+type EnvironmentQuery func(environment configuration.Environment, resources hci.Resources) (interface{}, error)
+
+// The outcome of a query in one environment
+type EnvironmentResult struct {
+	ServiceCode string
+	Environment configuration.Environment
+	Result      interface{}
+	Error       error
+}
+
+// Returned by the fan-out helpers when the query failed in at least one environment.
+// Results of the environments that succeeded are still returned alongside this error.
+type FanOutError struct {
+	Failures []EnvironmentResult
+}
+
+func (e FanOutError) Error() string {
+	errorStr := "Query failed in " + strconv.Itoa(len(e.Failures)) + " environment(s)\n"
+	for _, failure := range e.Failures {
+		errorStr += "[" + failure.ServiceCode + "/" + failure.Environment.Name + "] " + failure.Error.Error() + "\n"
+	}
+	return errorStr
+}
+
+// An instance tagged with the environment it was found in
+type EnvironmentInstance struct {
+	ServiceCode     string
+	EnvironmentId   string
+	EnvironmentName string
+	Instance        hci.Instance
+}
+
+// A public IP tagged with the environment it was found in
+type EnvironmentPublicIp struct {
+	ServiceCode     string
+	EnvironmentId   string
+	EnvironmentName string
+	PublicIp        hci.PublicIp
+}
+
+func (filter EnvironmentFilter) matches(environment configuration.Environment) bool {
+	if len(filter.ServiceCodes) > 0 && !containsString(filter.ServiceCodes, environment.ServiceConnection.ServiceCode) {
+		return false
+	}
+	if len(filter.EnvironmentNames) > 0 && !containsString(filter.EnvironmentNames, environment.Name) {
+		return false
+	}
+	return filter.Match == nil || filter.Match(environment)
+}
+
+// Run the query concurrently in every environment matching the filter.
+// One result is returned per environment, in the order the environments were listed.
+// The error return value is reserved for failures to list the environments.
+func (c HciClient) FanOut(filter EnvironmentFilter, query EnvironmentQuery) ([]EnvironmentResult, error) {
+	return c.FanOutWithConcurrency(filter, DEFAULT_FAN_OUT_CONCURRENCY, query)
+}
+
+// Same as FanOut, but with a custom limit on the number of environments queried at the same time
+func (c HciClient) FanOutWithConcurrency(filter EnvironmentFilter, concurrency int, query EnvironmentQuery) ([]EnvironmentResult, error) {
+	environments, err := c.Environments.List()
+	if err != nil {
+		return nil, err
+	}
+	selected := []configuration.Environment{}
+	for _, environment := range environments {
+		if filter.matches(environment) {
+			selected = append(selected, environment)
+		}
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]EnvironmentResult, len(selected))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, environment := range selected {
+		wg.Add(1)
+		go func(i int, environment configuration.Environment) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			serviceCode := environment.ServiceConnection.ServiceCode
+			resources := hci.NewResources(c.apiClient, serviceCode, environment.Name)
+			result, err := query(environment, resources)
+			results[i] = EnvironmentResult{
+				ServiceCode: serviceCode,
+				Environment: environment,
+				Result:      result,
+				Error:       err,
+			}
+		}(i, environment)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// List the instances of every environment matching the filter
+func (c HciClient) ListInstancesInEnvironments(filter EnvironmentFilter) ([]EnvironmentInstance, error) {
+	results, err := c.FanOut(filter, func(environment configuration.Environment, resources hci.Resources) (interface{}, error) {
+		return resources.Instances.List()
+	})
+	if err != nil {
+		return nil, err
+	}
+	instances := []EnvironmentInstance{}
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		for _, instance := range result.Result.([]hci.Instance) {
+			instances = append(instances, EnvironmentInstance{
+				ServiceCode:     result.ServiceCode,
+				EnvironmentId:   result.Environment.Id,
+				EnvironmentName: result.Environment.Name,
+				Instance:        instance,
+			})
+		}
+	}
+	return instances, failuresOf(results)
+}
+
+// List the public IPs of every environment matching the filter
+func (c HciClient) ListPublicIpsInEnvironments(filter EnvironmentFilter) ([]EnvironmentPublicIp, error) {
+	results, err := c.FanOut(filter, func(environment configuration.Environment, resources hci.Resources) (interface{}, error) {
+		return resources.PublicIps.List()
+	})
+	if err != nil {
+		return nil, err
+	}
+	publicIps := []EnvironmentPublicIp{}
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		for _, publicIp := range result.Result.([]hci.PublicIp) {
+			publicIps = append(publicIps, EnvironmentPublicIp{
+				ServiceCode:     result.ServiceCode,
+				EnvironmentId:   result.Environment.Id,
+				EnvironmentName: result.Environment.Name,
+				PublicIp:        publicIp,
+			})
+		}
+	}
+	return publicIps, failuresOf(results)
+}
+
+// Returns a FanOutError holding the failed results, or nil if all queries succeeded
+func failuresOf(results []EnvironmentResult) error {
+	failures := []EnvironmentResult{}
+	for _, result := range results {
+		if result.Error != nil {
+			failures = append(failures, result)
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return FanOutError{Failures: failures}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
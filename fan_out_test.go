@@ -0,0 +1,104 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/configuration"
+	"github.com/hypertec-cloud/go-hci/mocks/api_mocks"
+	"github.com/hypertec-cloud/go-hci/services/hci"
+	"github.com/stretchr/testify/assert"
+)
+
+// Builds a HciClient whose API answers with the response registered for the requested endpoint
+func buildTestFanOutClient(ctrl *gomock.Controller, environments []configuration.Environment, responses map[string]api.HciResponse) *HciClient {
+	mockApiClient := api_mocks.NewMockApiClient(ctrl)
+	mockApiClient.EXPECT().Do(gomock.Any()).AnyTimes().DoAndReturn(func(request api.HciRequest) (*api.HciResponse, error) {
+		if response, ok := responses[request.Endpoint]; ok {
+			return &response, nil
+		}
+		return &api.HciResponse{StatusCode: api.NOT_FOUND, Errors: []api.HciError{{ErrorCode: "NOT_FOUND"}}}, nil
+	})
+	client := NewHciClientWithApiClient(mockApiClient)
+	client.Environments = fakeEnvironmentService{environments: environments}
+	return client
+}
+
+func TestListInstancesInEnvironmentsTagsInstancesWithTheirEnvironment(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+		buildTestEnvironment("env2", "prod", "compute-on"),
+	}, map[string]api.HciResponse{
+		"/services/compute-qc/dev/instances":  {StatusCode: api.OK, Data: []byte(`[{"id":"i1"}]`)},
+		"/services/compute-on/prod/instances": {StatusCode: api.OK, Data: []byte(`[{"id":"i2"},{"id":"i3"}]`)},
+	})
+
+	//when
+	instances, err := client.ListInstancesInEnvironments(EnvironmentFilter{})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, []EnvironmentInstance{
+		{ServiceCode: "compute-qc", EnvironmentId: "env1", EnvironmentName: "dev", Instance: hci.Instance{Id: "i1"}},
+		{ServiceCode: "compute-on", EnvironmentId: "env2", EnvironmentName: "prod", Instance: hci.Instance{Id: "i2"}},
+		{ServiceCode: "compute-on", EnvironmentId: "env2", EnvironmentName: "prod", Instance: hci.Instance{Id: "i3"}},
+	}, instances)
+}
+
+func TestFanOutOnlyQueriesFilteredEnvironments(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+		buildTestEnvironment("env2", "prod", "compute-qc"),
+		buildTestEnvironment("env3", "prod", "compute-on"),
+	}, map[string]api.HciResponse{})
+
+	//when
+	results, err := client.FanOut(EnvironmentFilter{
+		ServiceCodes:     []string{"compute-qc"},
+		EnvironmentNames: []string{"prod"},
+	}, func(environment configuration.Environment, resources hci.Resources) (interface{}, error) {
+		return resources.GetEnvironmentName(), nil
+	})
+
+	//then
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "env2", results[0].Environment.Id)
+		assert.Equal(t, "prod", results[0].Result)
+	}
+}
+
+func TestListPublicIpsInEnvironmentsReturnsPartialResultsWithFanOutError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+		buildTestEnvironment("env2", "prod", "compute-qc"),
+	}, map[string]api.HciResponse{
+		"/services/compute-qc/dev/publicipaddresses": {StatusCode: api.OK, Data: []byte(`[{"id":"ip1"}]`)},
+	})
+
+	//when
+	publicIps, err := client.ListPublicIpsInEnvironments(EnvironmentFilter{})
+
+	//then
+	assert.Equal(t, []EnvironmentPublicIp{
+		{ServiceCode: "compute-qc", EnvironmentId: "env1", EnvironmentName: "dev", PublicIp: hci.PublicIp{Id: "ip1"}},
+	}, publicIps)
+	if fanOutError, ok := err.(FanOutError); assert.True(t, ok) {
+		if assert.Len(t, fanOutError.Failures, 1) {
+			assert.Equal(t, "prod", fanOutError.Failures[0].Environment.Name)
+		}
+	}
+}
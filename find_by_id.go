@@ -0,0 +1,127 @@
+package hci
+
+import (
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/configuration"
+	"github.com/hypertec-cloud/go-hci/services/hci"
+)
+
+// Entity type reported by FindByID for baremetal servers, which share the instances entity type with virtual instances
+const BAREMETAL_FOUND_ENTITY_TYPE = "baremetals"
+
+// Returned by FindByID when no entity with the id exists in the accessible environments
+type EntityNotFoundError struct {
+	Id string
+}
+
+func (e EntityNotFoundError) Error() string {
+	return "No entity found with id " + e.Id + " in the accessible environments"
+}
+
+// An entity found by FindByID. Entity holds a pointer to the hci struct matching EntityType
+// (ex: *hci.Instance, or *hci.Baremetal for BAREMETAL_FOUND_ENTITY_TYPE).
+type FoundEntity struct {
+	EntityType  string
+	Entity      interface{}
+	ServiceCode string
+	Environment configuration.Environment
+}
+
+type entityLookup struct {
+	entityType string
+	get        func(resources hci.Resources, id string) (interface{}, error)
+}
+
+// Entity types searched by FindByID, in search order
+var entityLookups = []entityLookup{
+	{hci.INSTANCE_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.Instances.Get(id)
+	}},
+	{hci.VOLUME_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.Volumes.Get(id)
+	}},
+	{hci.NETWORK_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.Networks.Get(id)
+	}},
+	{hci.VPC_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.Vpcs.Get(id)
+	}},
+	{hci.PUBLIC_IP_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.PublicIps.Get(id)
+	}},
+	{hci.TEMPLATE_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.Templates.Get(id)
+	}},
+	{hci.NETWORK_ACL_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.NetworkAcls.Get(id)
+	}},
+	{hci.NETWORK_ACL_RULE_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.NetworkAclRules.Get(id)
+	}},
+	{hci.PORT_FORWARDING_RULE_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.PortForwardingRules.Get(id)
+	}},
+	{hci.LOAD_BALANCER_RULE_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.LoadBalancerRules.Get(id)
+	}},
+	{hci.AFFINITY_GROUP_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.AffinityGroups.Get(id)
+	}},
+	{hci.RECOVERY_POINT_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.RecoveryPoints.Get(id)
+	}},
+	{hci.SNAPSHOT_ENTITY_TYPE, func(resources hci.Resources, id string) (interface{}, error) {
+		return resources.Snapshots.Get(id)
+	}},
+}
+
+// Search every accessible environment for an entity with the specified id.
+// Returns an EntityNotFoundError if the id does not exist, or a FanOutError if it was not found
+// but some environments could not be searched.
+func (c HciClient) FindByID(id string) (*FoundEntity, error) {
+	results, err := c.FanOut(EnvironmentFilter{}, func(environment configuration.Environment, resources hci.Resources) (interface{}, error) {
+		return findInEnvironment(resources, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if found, ok := result.Result.(*FoundEntity); ok && found != nil {
+			found.ServiceCode = result.ServiceCode
+			found.Environment = result.Environment
+			return found, nil
+		}
+	}
+	if failures := failuresOf(results); failures != nil {
+		return nil, failures
+	}
+	return nil, EntityNotFoundError{Id: id}
+}
+
+// Try every entity type in the environment. Returns a nil entity if the id was not found.
+// An entity type that cannot be searched does not stop the search: its error is only returned if nothing matched.
+func findInEnvironment(resources hci.Resources, id string) (*FoundEntity, error) {
+	var lookupErr error
+	for _, lookup := range entityLookups {
+		entity, err := lookup.get(resources, id)
+		if instance, ok := entity.(*hci.Instance); err == nil && ok && instance.IsBaremetal() {
+			baremetal, err := resources.Baremetals.Get(id)
+			if err != nil {
+				return nil, err
+			}
+			return &FoundEntity{EntityType: BAREMETAL_FOUND_ENTITY_TYPE, Entity: baremetal}, nil
+		}
+		if err == nil {
+			return &FoundEntity{EntityType: lookup.entityType, Entity: entity}, nil
+		}
+		if !isNotFound(err) && lookupErr == nil {
+			lookupErr = err
+		}
+	}
+	return nil, lookupErr
+}
+
+func isNotFound(err error) bool {
+	hciError, ok := err.(api.HciErrorResponse)
+	return ok && hciError.StatusCode == api.NOT_FOUND
+}
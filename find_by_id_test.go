@@ -0,0 +1,114 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/configuration"
+	"github.com/hypertec-cloud/go-hci/services/hci"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindByIDReturnsEntityWithTypeAndEnvironment(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+		buildTestEnvironment("env2", "prod", "compute-qc"),
+	}, map[string]api.HciResponse{
+		"/services/compute-qc/prod/volumes/some_id": {StatusCode: api.OK, Data: []byte(`{"id":"some_id","name":"data"}`)},
+	})
+
+	//when
+	found, err := client.FindByID("some_id")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, hci.VOLUME_ENTITY_TYPE, found.EntityType)
+		assert.Equal(t, &hci.Volume{Id: "some_id", Name: "data"}, found.Entity)
+		assert.Equal(t, "compute-qc", found.ServiceCode)
+		assert.Equal(t, "env2", found.Environment.Id)
+	}
+}
+
+func TestFindByIDReportsBaremetalServers(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+	}, map[string]api.HciResponse{
+		"/services/compute-qc/dev/instances/some_id": {StatusCode: api.OK, Data: []byte(`{"id":"some_id","hypervisor":"BareMetal"}`)},
+	})
+
+	//when
+	found, err := client.FindByID("some_id")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, BAREMETAL_FOUND_ENTITY_TYPE, found.EntityType)
+		assert.Equal(t, &hci.Baremetal{Id: "some_id", Hypervisor: hci.HYPERVISOR_BAREMETAL}, found.Entity)
+	}
+}
+
+func TestFindByIDSearchesRemainingEntityTypesAfterAnError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+	}, map[string]api.HciResponse{
+		"/services/compute-qc/dev/instances/some_id": {StatusCode: api.BAD_REQUEST, Errors: []api.HciError{{ErrorCode: "FORBIDDEN"}}},
+		"/services/compute-qc/dev/templates/some_id": {StatusCode: api.OK, Data: []byte(`{"id":"some_id","name":"golden"}`)},
+	})
+
+	//when
+	found, err := client.FindByID("some_id")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, hci.TEMPLATE_ENTITY_TYPE, found.EntityType)
+		assert.Equal(t, &hci.Template{ID: "some_id", Name: "golden"}, found.Entity)
+	}
+}
+
+func TestFindByIDReturnsEntityNotFoundErrorIfMissingEverywhere(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+	}, map[string]api.HciResponse{})
+
+	//when
+	found, err := client.FindByID("some_id")
+
+	//then
+	assert.Nil(t, found)
+	assert.Equal(t, EntityNotFoundError{Id: "some_id"}, err)
+}
+
+func TestFindByIDReturnsFanOutErrorIfAnEnvironmentCouldNotBeSearched(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := buildTestFanOutClient(ctrl, []configuration.Environment{
+		buildTestEnvironment("env1", "dev", "compute-qc"),
+	}, map[string]api.HciResponse{
+		"/services/compute-qc/dev/instances/some_id": {StatusCode: api.BAD_REQUEST, Errors: []api.HciError{{ErrorCode: "FORBIDDEN"}}},
+	})
+
+	//when
+	_, err := client.FindByID("some_id")
+
+	//then
+	_, ok := err.(FanOutError)
+	assert.True(t, ok)
+}
@@ -5,6 +5,7 @@ package services_mocks
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/services"
 )
 
 // Mock of EntityService interface
@@ -93,3 +94,46 @@ func (_m *MockEntityService) Delete(id string, body []byte, options map[string]s
 func (_mr *_MockEntityServiceRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Delete", arg0, arg1, arg2)
 }
+
+// Mock of AsyncEntityService interface
+type MockAsyncEntityService struct {
+	ctrl     *gomock.Controller
+	recorder *_MockAsyncEntityServiceRecorder
+}
+
+// Recorder for MockAsyncEntityService (not exported)
+type _MockAsyncEntityServiceRecorder struct {
+	mock *MockAsyncEntityService
+}
+
+func NewMockAsyncEntityService(ctrl *gomock.Controller) *MockAsyncEntityService {
+	mock := &MockAsyncEntityService{ctrl: ctrl}
+	mock.recorder = &_MockAsyncEntityServiceRecorder{mock}
+	return mock
+}
+
+func (_m *MockAsyncEntityService) EXPECT() *_MockAsyncEntityServiceRecorder {
+	return _m.recorder
+}
+
+func (_m *MockAsyncEntityService) CreateAsync(body []byte, options map[string]string) (*services.Task, error) {
+	ret := _m.ctrl.Call(_m, "CreateAsync", body, options)
+	ret0, _ := ret[0].(*services.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAsyncEntityServiceRecorder) CreateAsync(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateAsync", arg0, arg1)
+}
+
+func (_m *MockAsyncEntityService) GetTask(id string) (*services.Task, error) {
+	ret := _m.ctrl.Call(_m, "GetTask", id)
+	ret0, _ := ret[0].(*services.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockAsyncEntityServiceRecorder) GetTask(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetTask", arg0)
+}
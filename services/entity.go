@@ -1,6 +1,8 @@
 package services
 
 import (
+	"strings"
+
 	"github.com/hypertec-cloud/go-hci/api"
 )
 
@@ -14,6 +16,15 @@ type EntityService interface {
 	Delete(id string, body []byte, options map[string]string) ([]byte, error)
 }
 
+// Implemented by the entity services that can create an entity without waiting for the creation task to complete
+type AsyncEntityService interface {
+	CreateAsync(body []byte, options map[string]string) (*Task, error)
+	GetTask(id string) (*Task, error)
+}
+
+// Implementation of the EntityService, also able to create entities asynchronously
+var _ AsyncEntityService = &EntityApi{}
+
 // Implementation of the EntityService
 type EntityApi struct {
 	apiClient       api.ApiClient
@@ -113,6 +124,33 @@ func (entityApi *EntityApi) Create(body []byte, options map[string]string) ([]by
 	return entityApi.taskService.PollResponse(response, DEFAULT_POLLING_INTERVAL)
 }
 
+// Create a new entity described in the body parameter (json object) without waiting for the creation to complete.
+// Returns the task of the creation, already successful with the entity as result if the creation completed right away.
+// Use GetTask to follow a pending task.
+func (entityApi *EntityApi) CreateAsync(body []byte, options map[string]string) (*Task, error) {
+	request := api.HciRequest{
+		Method:   api.POST,
+		Body:     body,
+		Endpoint: entityApi.buildEndpoint(),
+		Options:  options,
+	}
+	response, err := entityApi.apiClient.Do(request)
+	if err != nil {
+		return nil, err
+	} else if response.IsError() || strings.EqualFold(response.TaskStatus, FAILED) {
+		return nil, api.HciErrorResponse(*response)
+	}
+	if strings.EqualFold(response.TaskStatus, SUCCESS) {
+		return &Task{Id: response.TaskId, Status: SUCCESS, Result: response.Data}, nil
+	}
+	return &Task{Id: response.TaskId, Status: PENDING}, nil
+}
+
+// Get the task with the specified id, ex: the task returned by CreateAsync
+func (entityApi *EntityApi) GetTask(id string) (*Task, error) {
+	return entityApi.taskService.Get(id)
+}
+
 // Update entity with specified id described in the body parameter (json object). Returns a []byte (of a json object) that should be unmarshalled to a specific entity
 func (entityApi *EntityApi) Update(id string, body []byte, options map[string]string) ([]byte, error) {
 	request := api.HciRequest{
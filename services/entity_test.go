@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/mocks/api_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAsyncReturnPendingTaskWithoutPolling(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHciClient := api_mocks.NewMockApiClient(ctrl)
+
+	entityService := EntityApi{
+		apiClient:       mockHciClient,
+		serviceCode:     "compute",
+		environmentName: "env",
+		entityType:      "instances",
+	}
+
+	mockHciClient.EXPECT().Do(api.HciRequest{
+		Method:   api.POST,
+		Body:     []byte(`{}`),
+		Endpoint: "/services/compute/env/instances",
+		Options:  map[string]string{"operation": "acquireBareMetal"},
+	}).Return(&api.HciResponse{StatusCode: 200, TaskId: TEST_TASK_ID, TaskStatus: PENDING}, nil)
+
+	//when
+	task, err := entityService.CreateAsync([]byte(`{}`), map[string]string{"operation": "acquireBareMetal"})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, Task{Id: TEST_TASK_ID, Status: PENDING}, *task)
+}
+
+func TestCreateAsyncReturnSuccessfulTaskIfCompleted(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHciClient := api_mocks.NewMockApiClient(ctrl)
+
+	entityService := EntityApi{
+		apiClient: mockHciClient,
+	}
+
+	mockHciClient.EXPECT().Do(gomock.Any()).Return(&api.HciResponse{StatusCode: 200, TaskId: TEST_TASK_ID, TaskStatus: SUCCESS, Data: []byte(`{"id":"new_id"}`)}, nil)
+
+	//when
+	task, err := entityService.CreateAsync([]byte(`{}`), map[string]string{})
+
+	//then
+	assert.NoError(t, err)
+	assert.True(t, task.Success())
+	assert.Equal(t, []byte(`{"id":"new_id"}`), task.Result)
+}
+
+func TestCreateAsyncReturnErrorIfTaskFailed(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHciClient := api_mocks.NewMockApiClient(ctrl)
+
+	entityService := EntityApi{
+		apiClient: mockHciClient,
+	}
+
+	mockHciClient.EXPECT().Do(gomock.Any()).Return(&api.HciResponse{StatusCode: 200, TaskId: TEST_TASK_ID, TaskStatus: FAILED}, nil)
+
+	//when
+	task, err := entityService.CreateAsync([]byte(`{}`), map[string]string{})
+
+	//then
+	assert.Nil(t, task)
+	assert.Error(t, err)
+}
@@ -8,12 +8,23 @@ import (
 	"github.com/hypertec-cloud/go-hci/services"
 )
 
+// Hypervisor of the baremetal servers. Baremetal servers and virtual instances share the instances entity type.
+const HYPERVISOR_BAREMETAL = "BareMetal"
+
+// List options filtering instances and baremetal servers by hypervisor on the server side and paging the results
+const (
+	LIST_OPTION_HYPERVISOR = "hypervisor"
+	LIST_OPTION_PAGE       = "page"
+	LIST_OPTION_PAGE_SIZE  = "pageSize"
+)
+
 const (
 	BAREMETAL_STATE_RUNNING = "Running"
 	BAREMETAL_STATE_STOPPED = "Stopped"
 )
 
 const (
+	BAREMETAL_ACQUIRE_OPERATION                 = "acquireBareMetal"
 	BAREMETAL_START_OPERATION                   = "start"
 	BAREMETAL_STOP_OPERATION                    = "stop"
 	BAREMETAL_REBOOT_OPERATION                  = "reboot"
@@ -21,6 +32,9 @@ const (
 	BAREMETAL_PURGE_OPERATION                   = "releaseBareMetal"
 	BAREMETAL_CHANGE_NETWORK_OFFERING_OPERATION = "changeNetwork"
 	BAREMETAL_ASSOCIATE_SSH_KEY_OPERATION       = "associateSSHKey"
+	BAREMETAL_RESET_PASSWORD_OPERATION          = "resetPassword"
+	BAREMETAL_CREATE_RECOVERY_POINT_OPERATION   = "createRecoveryPoint"
+	BAREMETAL_CHANGE_COMPUTE_OFFERING_OPERATION = "changeComputeOffering"
 )
 
 type Baremetal struct {
@@ -28,12 +42,12 @@ type Baremetal struct {
 	Name                     string        `json:"name,omitempty"`
 	State                    string        `json:"state,omitempty"`
 	TemplateId               string        `json:"templateId,omitempty"`
-	ImageId                  string        `json:"imageId,omitempty"` // This bug must be fixed in CloudMC API
+	ImageId                  string        `json:"imageId,omitempty"` // The API names the template imageId. Create fills it from TemplateId and TemplateId is filled from it when parsing.
 	TemplateName             string        `json:"templateName,omitempty"`
 	IsPasswordEnabled        bool          `json:"isPasswordEnabled,omitempty"`
 	IsSSHKeyEnabled          bool          `json:"isSshKeyEnabled,omitempty"`
 	Username                 string        `json:"username,omitempty"`
-	Password                 string        `json:"password,omitempty"`
+	Password                 SecretString  `json:"password,omitempty"`
 	SSHKeyName               string        `json:"sshKeyName,omitempty"`
 	Hypervisor               string        `json:"hypervisor,omitempty"`
 	ComputeOfferingId        string        `json:"computeOfferingId,omitempty"`
@@ -78,6 +92,7 @@ type BaremetalService interface {
 	List() ([]Baremetal, error)
 	ListWithOptions(options map[string]string) ([]Baremetal, error)
 	Create(Baremetal) (*Baremetal, error)
+	CreateAndTrack(Baremetal, BaremetalProvisioningOptions) (*Baremetal, error)
 	Destroy(id string) (bool, error)
 	Recover(id string) (bool, error)
 	Exists(id string) (bool, error)
@@ -85,6 +100,17 @@ type BaremetalService interface {
 	Stop(id string) (bool, error)
 	AssociateSSHKey(id string, sshKeyName string) (bool, error)
 	Reboot(id string) (bool, error)
+	Update(Baremetal) (*Baremetal, error)
+	ChangeComputeOffering(Baremetal) (bool, error)
+	ChangeNetwork(id string, newNetworkId string) (bool, error)
+	ResetPassword(id string) (string, error)
+	CreateRecoveryPoint(id string, recoveryPoint RecoveryPoint) (bool, error)
+}
+
+// Baremetal as sent on creation, with the actual password instead of the redacted one
+type baremetalCreate struct {
+	Baremetal
+	Password string `json:"password,omitempty"`
 }
 
 type BaremetalApi struct {
@@ -100,15 +126,30 @@ func NewBaremetalService(apiClient api.ApiClient, serviceCode string, environmen
 func parseBaremetal(data []byte) *Baremetal {
 	baremetal := Baremetal{}
 	json.Unmarshal(data, &baremetal)
+	baremetal.fillTemplateId()
 	return &baremetal
 }
 
 func parseBaremetalList(data []byte) []Baremetal {
 	baremetals := []Baremetal{}
 	json.Unmarshal(data, &baremetals)
+	for i := range baremetals {
+		baremetals[i].fillTemplateId()
+	}
 	return baremetals
 }
 
+func isBaremetalHypervisor(hypervisor string) bool {
+	return strings.EqualFold(hypervisor, HYPERVISOR_BAREMETAL)
+}
+
+// Use the imageId returned by the API as TemplateId when the templateId is missing
+func (baremetal *Baremetal) fillTemplateId() {
+	if baremetal.TemplateId == "" {
+		baremetal.TemplateId = baremetal.ImageId
+	}
+}
+
 // Get baremetal with the specified id for the current environment
 func (BaremetalApi *BaremetalApi) Get(id string) (*Baremetal, error) {
 	data, err := BaremetalApi.entityService.Get(id, map[string]string{})
@@ -124,8 +165,13 @@ func (BaremetalApi *BaremetalApi) List() ([]Baremetal, error) {
 }
 
 // List all baremetals for the current environment. Can use options to do sorting and paging.
+// Virtual instances are filtered out by the server with the hypervisor option, see the Instances service to list them.
 func (BaremetalApi *BaremetalApi) ListWithOptions(options map[string]string) ([]Baremetal, error) {
-	data, err := BaremetalApi.entityService.List(options)
+	optionsCopy := map[string]string{LIST_OPTION_HYPERVISOR: HYPERVISOR_BAREMETAL}
+	for k, v := range options {
+		optionsCopy[k] = v
+	}
+	data, err := BaremetalApi.entityService.List(optionsCopy)
 	if err != nil {
 		return nil, err
 	}
@@ -133,13 +179,17 @@ func (BaremetalApi *BaremetalApi) ListWithOptions(options map[string]string) ([]
 }
 
 // Create a baremetal in the current environment
+// The TemplateId is sent as the imageId expected by the API
 func (BaremetalApi *BaremetalApi) Create(baremetal Baremetal) (*Baremetal, error) {
-	send, merr := json.Marshal(baremetal)
+	if baremetal.ImageId == "" {
+		baremetal.ImageId = baremetal.TemplateId
+	}
+	send, merr := json.Marshal(baremetalCreate{Baremetal: baremetal, Password: baremetal.Password.Reveal()})
 	if merr != nil {
 		return nil, merr
 	}
 	optionsCopy := map[string]string{}
-	optionsCopy["operation"] = "acquireBareMetal"
+	optionsCopy["operation"] = BAREMETAL_ACQUIRE_OPERATION
 
 	body, err := BaremetalApi.entityService.Create(send, optionsCopy)
 	if err != nil {
@@ -203,3 +253,64 @@ func (BaremetalApi *BaremetalApi) Reboot(id string) (bool, error) {
 	_, err := BaremetalApi.entityService.Execute(id, BAREMETAL_REBOOT_OPERATION, []byte{}, map[string]string{})
 	return err == nil, err
 }
+
+// Update the name and user data of the baremetal with the specified id in the current environment.
+// Empty values are left unchanged.
+func (BaremetalApi *BaremetalApi) Update(baremetal Baremetal) (*Baremetal, error) {
+	send, merr := json.Marshal(Baremetal{
+		Name:     baremetal.Name,
+		UserData: baremetal.UserData,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+	body, err := BaremetalApi.entityService.Update(baremetal.Id, send, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseBaremetal(body), nil
+}
+
+// Change the compute offering of the baremetal with the specified id in the current environment
+// Note: This will reboot your baremetal if running
+func (BaremetalApi *BaremetalApi) ChangeComputeOffering(baremetal Baremetal) (bool, error) {
+	send, merr := json.Marshal(baremetal)
+	if merr != nil {
+		return false, merr
+	}
+	_, err := BaremetalApi.entityService.Execute(baremetal.Id, BAREMETAL_CHANGE_COMPUTE_OFFERING_OPERATION, send, map[string]string{})
+	return err == nil, err
+}
+
+// Change the network of the baremetal with the specified id
+// Note: This will reboot your baremetal, remove all pfrs of this baremetal and remove the baremetal from all lbrs.
+func (BaremetalApi *BaremetalApi) ChangeNetwork(id string, networkId string) (bool, error) {
+	send, merr := json.Marshal(Baremetal{NetworkId: networkId})
+	if merr != nil {
+		return false, merr
+	}
+	_, err := BaremetalApi.entityService.Execute(id, BAREMETAL_CHANGE_NETWORK_OFFERING_OPERATION, send, map[string]string{})
+	return err == nil, err
+}
+
+// Reset the password of the baremetal with the specified id in the current environment
+func (BaremetalApi *BaremetalApi) ResetPassword(id string) (string, error) {
+	body, err := BaremetalApi.entityService.Execute(id, BAREMETAL_RESET_PASSWORD_OPERATION, []byte{}, map[string]string{})
+	if err != nil {
+		return "", err
+	}
+	baremetal := parseBaremetal(body)
+	return baremetal.Password.Reveal(), nil
+}
+
+// Create a recovery point of the baremetal with the specified id in the current environment
+func (BaremetalApi *BaremetalApi) CreateRecoveryPoint(id string, recoveryPoint RecoveryPoint) (bool, error) {
+	send, merr := json.Marshal(Baremetal{
+		RecoveryPoint: recoveryPoint,
+	})
+	if merr != nil {
+		return false, merr
+	}
+	_, err := BaremetalApi.entityService.Execute(id, BAREMETAL_CREATE_RECOVERY_POINT_OPERATION, send, map[string]string{})
+	return err == nil, err
+}
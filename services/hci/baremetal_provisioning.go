@@ -0,0 +1,145 @@
+package hci
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hypertec-cloud/go-hci/services"
+)
+
+// Acquiring a baremetal server takes much longer than creating a virtual instance
+const (
+	DEFAULT_BAREMETAL_PROVISIONING_TIMEOUT       = 2 * time.Hour
+	DEFAULT_BAREMETAL_PROVISIONING_ESTIMATE      = 30 * time.Minute
+	DEFAULT_BAREMETAL_PROVISIONING_POLL_INTERVAL = 15 * time.Second
+)
+
+// Reported to the OnProgress callback every time the provisioning task is polled
+type BaremetalProvisioningProgress struct {
+	TaskId string
+	// Percentage of completion reported by the task, 0 if unknown
+	Progress int
+	// Id and state of the server, empty until the platform reports them
+	BaremetalId         string
+	State               string
+	Elapsed             time.Duration
+	EstimatedCompletion time.Time
+	Done                bool
+}
+
+type BaremetalProvisioningOptions struct {
+	// Defaults to DEFAULT_BAREMETAL_PROVISIONING_TIMEOUT
+	Timeout time.Duration
+	// Used for the estimated completion until the task reports its progress. Defaults to DEFAULT_BAREMETAL_PROVISIONING_ESTIMATE.
+	EstimatedDuration time.Duration
+	// Defaults to DEFAULT_BAREMETAL_PROVISIONING_POLL_INTERVAL
+	PollInterval time.Duration
+	OnProgress   func(progress BaremetalProvisioningProgress)
+	// Defaults to the system clock
+	Clock Clock
+}
+
+// Returned when a baremetal server is still being provisioned after the timeout.
+// The provisioning goes on: the server may still become available later.
+type BaremetalProvisioningTimeoutError struct {
+	TaskId  string
+	Timeout time.Duration
+}
+
+func (e BaremetalProvisioningTimeoutError) Error() string {
+	return "Baremetal provisioning task " + e.TaskId + " still pending after " + e.Timeout.String()
+}
+
+// Acquire a baremetal server in the current environment and wait for it to be provisioned,
+// reporting the progress of the task and the intermediate states of the server to OnProgress
+func (BaremetalApi *BaremetalApi) CreateAndTrack(baremetal Baremetal, options BaremetalProvisioningOptions) (*Baremetal, error) {
+	if options.Timeout <= 0 {
+		options.Timeout = DEFAULT_BAREMETAL_PROVISIONING_TIMEOUT
+	}
+	if options.EstimatedDuration <= 0 {
+		options.EstimatedDuration = DEFAULT_BAREMETAL_PROVISIONING_ESTIMATE
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DEFAULT_BAREMETAL_PROVISIONING_POLL_INTERVAL
+	}
+	if options.OnProgress == nil {
+		options.OnProgress = func(BaremetalProvisioningProgress) {}
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	asyncEntityService, ok := BaremetalApi.entityService.(services.AsyncEntityService)
+	if !ok {
+		return nil, fmt.Errorf("Baremetal service cannot create baremetals asynchronously")
+	}
+	if baremetal.ImageId == "" {
+		baremetal.ImageId = baremetal.TemplateId
+	}
+	send, merr := json.Marshal(baremetalCreate{Baremetal: baremetal, Password: baremetal.Password.Reveal()})
+	if merr != nil {
+		return nil, merr
+	}
+	task, err := asyncEntityService.CreateAsync(send, map[string]string{"operation": BAREMETAL_ACQUIRE_OPERATION})
+	if err != nil {
+		return nil, err
+	}
+	start := options.Clock.Now()
+	for {
+		if task.Failed() {
+			return nil, services.FailedTask(*task)
+		}
+		progress := BaremetalApi.provisioningProgress(task, start, options)
+		options.OnProgress(progress)
+		if progress.Done {
+			return parseBaremetal(task.Result), nil
+		}
+		if progress.Elapsed >= options.Timeout {
+			return nil, BaremetalProvisioningTimeoutError{TaskId: task.Id, Timeout: options.Timeout}
+		}
+		<-options.Clock.After(options.PollInterval)
+		task, err = asyncEntityService.GetTask(task.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Build the progress of the task. A pending task may already return the server, whose state is then fetched.
+func (BaremetalApi *BaremetalApi) provisioningProgress(task *services.Task, start time.Time, options BaremetalProvisioningOptions) BaremetalProvisioningProgress {
+	now := options.Clock.Now()
+	progress := BaremetalProvisioningProgress{
+		TaskId:              task.Id,
+		Progress:            task.Progress,
+		Elapsed:             now.Sub(start),
+		EstimatedCompletion: start.Add(options.EstimatedDuration),
+		Done:                task.Success(),
+	}
+	if progress.Done {
+		progress.Progress = 100
+	}
+	if progress.Progress > 0 {
+		progress.EstimatedCompletion = start.Add(progress.Elapsed * 100 / time.Duration(progress.Progress))
+	}
+	if len(task.Result) == 0 {
+		return progress
+	}
+	server := parseBaremetal(task.Result)
+	progress.BaremetalId = server.Id
+	progress.State = server.State
+	if !progress.Done && server.Id != "" {
+		if current, err := BaremetalApi.Get(server.Id); err == nil {
+			progress.State = current.State
+		}
+	}
+	return progress
+}
+
+func (progress BaremetalProvisioningProgress) String() string {
+	str := "Baremetal provisioning task " + progress.TaskId + ": " + strconv.Itoa(progress.Progress) + "%"
+	if progress.State != "" {
+		str += ", server " + progress.BaremetalId + " " + progress.State
+	}
+	return str + ", elapsed " + progress.Elapsed.String()
+}
@@ -0,0 +1,120 @@
+package hci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/hypertec-cloud/go-hci/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// An entity service mock that can also create entities asynchronously
+type asyncEntityServiceMock struct {
+	*services_mocks.MockEntityService
+	*services_mocks.MockAsyncEntityService
+}
+
+func TestCreateAndTrackReportsProgressUntilProvisioned(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockAsyncEntityService := services_mocks.NewMockAsyncEntityService(ctrl)
+	baremetalService := BaremetalApi{entityService: asyncEntityServiceMock{mockEntityService, mockAsyncEntityService}}
+
+	mockAsyncEntityService.EXPECT().CreateAsync(gomock.Any(), map[string]string{"operation": BAREMETAL_ACQUIRE_OPERATION}).Return(&services.Task{Id: "task1", Status: services.PENDING}, nil)
+	gomock.InOrder(
+		mockAsyncEntityService.EXPECT().GetTask("task1").Return(&services.Task{Id: "task1", Status: services.PENDING, Progress: 50, Result: []byte(`{"id":"bm1","state":"Starting"}`)}, nil),
+		mockEntityService.EXPECT().Get("bm1", gomock.Any()).Return([]byte(`{"id":"bm1","state":"Installing"}`), nil),
+		mockAsyncEntityService.EXPECT().GetTask("task1").Return(&services.Task{Id: "task1", Status: services.SUCCESS, Result: []byte(`{"id":"bm1","state":"Running"}`)}, nil),
+	)
+
+	progresses := []BaremetalProvisioningProgress{}
+	start := time.Date(2019, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	//when
+	baremetal, err := baremetalService.CreateAndTrack(Baremetal{Name: "bm", TemplateId: "template"}, BaremetalProvisioningOptions{
+		PollInterval: 10 * time.Minute,
+		Clock:        &steppingClock{now: start},
+		OnProgress: func(progress BaremetalProvisioningProgress) {
+			progresses = append(progresses, progress)
+		},
+	})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, Baremetal{Id: "bm1", State: "Running"}, *baremetal)
+	}
+	if assert.Len(t, progresses, 3) {
+		assert.Equal(t, start.Add(DEFAULT_BAREMETAL_PROVISIONING_ESTIMATE), progresses[0].EstimatedCompletion)
+		assert.Equal(t, 50, progresses[1].Progress)
+		assert.Equal(t, "Installing", progresses[1].State)
+		assert.Equal(t, start.Add(20*time.Minute), progresses[1].EstimatedCompletion)
+		assert.True(t, progresses[2].Done)
+		assert.Equal(t, 100, progresses[2].Progress)
+	}
+}
+
+func TestCreateAndTrackReturnsErrorOnTimeout(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockAsyncEntityService := services_mocks.NewMockAsyncEntityService(ctrl)
+	baremetalService := BaremetalApi{entityService: asyncEntityServiceMock{mockEntityService, mockAsyncEntityService}}
+
+	mockAsyncEntityService.EXPECT().CreateAsync(gomock.Any(), gomock.Any()).Return(&services.Task{Id: "task1", Status: services.PENDING}, nil)
+	mockAsyncEntityService.EXPECT().GetTask("task1").Return(&services.Task{Id: "task1", Status: services.PENDING}, nil).Times(2)
+
+	//when
+	_, err := baremetalService.CreateAndTrack(Baremetal{Name: "bm"}, BaremetalProvisioningOptions{
+		Timeout:      time.Hour,
+		PollInterval: 30 * time.Minute,
+		Clock:        &steppingClock{now: time.Now()},
+	})
+
+	//then
+	assert.Equal(t, BaremetalProvisioningTimeoutError{TaskId: "task1", Timeout: time.Hour}, err)
+}
+
+func TestCreateAndTrackReturnsErrorIfTaskFails(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockAsyncEntityService := services_mocks.NewMockAsyncEntityService(ctrl)
+	baremetalService := BaremetalApi{entityService: asyncEntityServiceMock{mockEntityService, mockAsyncEntityService}}
+
+	mockAsyncEntityService.EXPECT().CreateAsync(gomock.Any(), gomock.Any()).Return(&services.Task{Id: "task1", Status: services.PENDING}, nil)
+	mockAsyncEntityService.EXPECT().GetTask("task1").Return(&services.Task{Id: "task1", Status: services.FAILED}, nil)
+
+	//when
+	baremetal, err := baremetalService.CreateAndTrack(Baremetal{Name: "bm"}, BaremetalProvisioningOptions{
+		Clock: &steppingClock{now: time.Now()},
+	})
+
+	//then
+	assert.Nil(t, baremetal)
+	assert.IsType(t, services.FailedTask{}, err)
+}
+
+func TestCreateAndTrackReturnsErrorIfEntityServiceIsNotAsync(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	baremetalService := BaremetalApi{entityService: mockEntityService}
+
+	//when
+	baremetal, err := baremetalService.CreateAndTrack(Baremetal{Name: "bm"}, BaremetalProvisioningOptions{})
+
+	//then
+	assert.Nil(t, baremetal)
+	assert.Error(t, err)
+}
@@ -237,7 +237,7 @@ func TestCreateBaremetalReturnCreatedBaremetalIfSuccess(t *testing.T) {
 
 	//then
 	if assert.NotNil(t, createdBaremetal) {
-		assert.Equal(t, "new_password", createdBaremetal.Password)
+		assert.Equal(t, "new_password", createdBaremetal.Password.Reveal())
 	}
 }
 
@@ -585,3 +585,139 @@ func BMTestExistsReturnErrorIfUnexpectedError(t *testing.T) {
 	//then
 	assert.Equal(t, mockError, err)
 }
+
+func TestCreateBaremetalSendsTemplateIdAsImageId(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	baremetalService := BaremetalApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"name":"new_name","templateId":"templateId","imageId":"templateId","recoveryPoint":{}}`)
+	mockEntityService.EXPECT().Create(expectedBody, map[string]string{"operation": "acquireBareMetal"}).Return([]byte(`{"id":"new_id","imageId":"templateId"}`), nil)
+
+	//when
+	createdBaremetal, err := baremetalService.Create(Baremetal{Name: "new_name", TemplateId: "templateId"})
+
+	//then
+	assert.NoError(t, err)
+	if assert.NotNil(t, createdBaremetal) {
+		assert.Equal(t, "templateId", createdBaremetal.TemplateId)
+	}
+}
+
+func TestUpdateBaremetalReturnUpdatedBaremetalIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	baremetalService := BaremetalApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"name":"new_name","recoveryPoint":{}}`)
+	mockEntityService.EXPECT().Update(TEST_BAREMETAL_ID, expectedBody, gomock.Any()).Return([]byte(`{"id":"`+TEST_BAREMETAL_ID+`","name":"new_name"}`), nil)
+
+	//when
+	updatedBaremetal, err := baremetalService.Update(Baremetal{Id: TEST_BAREMETAL_ID, Name: "new_name", State: "Running"})
+
+	//then
+	assert.NoError(t, err)
+	if assert.NotNil(t, updatedBaremetal) {
+		assert.Equal(t, "new_name", updatedBaremetal.Name)
+	}
+}
+
+func TestChangeComputeOfferingBaremetalReturnTrueIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	baremetalService := BaremetalApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"id":"` + TEST_BAREMETAL_ID + `","newComputeOfferingId":"new_offering","recoveryPoint":{}}`)
+	mockEntityService.EXPECT().Execute(TEST_BAREMETAL_ID, BAREMETAL_CHANGE_COMPUTE_OFFERING_OPERATION, expectedBody, gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	success, err := baremetalService.ChangeComputeOffering(Baremetal{Id: TEST_BAREMETAL_ID, NewComputeOfferingId: "new_offering"})
+
+	//then
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
+
+func TestChangeNetworkBaremetalReturnTrueIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	baremetalService := BaremetalApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"networkId":"new_network","recoveryPoint":{}}`)
+	mockEntityService.EXPECT().Execute(TEST_BAREMETAL_ID, BAREMETAL_CHANGE_NETWORK_OFFERING_OPERATION, expectedBody, gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	success, err := baremetalService.ChangeNetwork(TEST_BAREMETAL_ID, "new_network")
+
+	//then
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
+
+func TestResetPasswordBaremetalReturnNewPasswordIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	baremetalService := BaremetalApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().Execute(TEST_BAREMETAL_ID, BAREMETAL_RESET_PASSWORD_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{"password":"new_password"}`), nil)
+
+	//when
+	newPassword, err := baremetalService.ResetPassword(TEST_BAREMETAL_ID)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "new_password", newPassword)
+}
+
+func TestCreateRecoveryPointBaremetalReturnFalseIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	baremetalService := BaremetalApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_create_recovery_point_error"}
+	expectedBody := []byte(`{"recoveryPoint":{"name":"rp","description":"desc"}}`)
+	mockEntityService.EXPECT().Execute(TEST_BAREMETAL_ID, BAREMETAL_CREATE_RECOVERY_POINT_OPERATION, expectedBody, gomock.Any()).Return(nil, mockError)
+
+	//when
+	success, err := baremetalService.CreateRecoveryPoint(TEST_BAREMETAL_ID, RecoveryPoint{Name: "rp", Description: "desc"})
+
+	//then
+	assert.False(t, success)
+	assert.Equal(t, mockError, err)
+}
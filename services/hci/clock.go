@@ -0,0 +1,21 @@
+package hci
+
+import (
+	"time"
+)
+
+// The source of time of the operations that wait or poll, such as RecoveryPointScheduler. Can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
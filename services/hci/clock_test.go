@@ -0,0 +1,21 @@
+package hci
+
+import (
+	"time"
+)
+
+// A clock moving forward by the requested delay every time After is called
+type steppingClock struct {
+	now time.Time
+}
+
+func (clock *steppingClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *steppingClock) After(d time.Duration) <-chan time.Time {
+	clock.now = clock.now.Add(d)
+	c := make(chan time.Time, 1)
+	c <- clock.now
+	return c
+}
@@ -0,0 +1,51 @@
+package hci
+
+// Kinds of compute resources returned by ListCompute
+const (
+	COMPUTE_KIND_VM        = "VM"
+	COMPUTE_KIND_BAREMETAL = "Baremetal"
+)
+
+// A virtual instance or a baremetal server. Only the field matching the Kind is set.
+type ComputeResource struct {
+	Kind      string
+	Id        string
+	Name      string
+	State     string
+	Instance  *Instance
+	Baremetal *Baremetal
+}
+
+// List the virtual instances and the baremetal servers of the current environment, virtual instances first
+func (resources Resources) ListCompute() ([]ComputeResource, error) {
+	instances, err := resources.Instances.List()
+	if err != nil {
+		return nil, err
+	}
+	baremetals, err := resources.Baremetals.List()
+	if err != nil {
+		return nil, err
+	}
+	computeResources := []ComputeResource{}
+	for i := range instances {
+		instance := instances[i]
+		computeResources = append(computeResources, ComputeResource{
+			Kind:     COMPUTE_KIND_VM,
+			Id:       instance.Id,
+			Name:     instance.Name,
+			State:    instance.State,
+			Instance: &instance,
+		})
+	}
+	for i := range baremetals {
+		baremetal := baremetals[i]
+		computeResources = append(computeResources, ComputeResource{
+			Kind:      COMPUTE_KIND_BAREMETAL,
+			Id:        baremetal.Id,
+			Name:      baremetal.Name,
+			State:     baremetal.State,
+			Baremetal: &baremetal,
+		})
+	}
+	return computeResources, nil
+}
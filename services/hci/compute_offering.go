@@ -2,22 +2,83 @@ package hci
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+
 	"github.com/hypertec-cloud/go-hci/api"
 	"github.com/hypertec-cloud/go-hci/services"
 )
 
 type ComputeOffering struct {
-	Id         string `json:"id,omitempty"`
-	Name       string `json:"name,omitempty"`
-	MemoryInMB int    `json:"memoryInMB,omitempty"`
-	CpuCount   int    `json:"cpuCount,omitempty"`
-	Custom     bool   `json:"custom,omitempty"`
+	Id            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	MemoryInMB    int    `json:"memoryInMB,omitempty"`
+	CpuCount      int    `json:"cpuCount,omitempty"`
+	Custom        bool   `json:"custom,omitempty"`
+	MinCpuCount   int    `json:"minCpuCount,omitempty"`
+	MaxCpuCount   int    `json:"maxCpuCount,omitempty"`
+	MinMemoryInMB int    `json:"minMemoryInMB,omitempty"`
+	MaxMemoryInMB int    `json:"maxMemoryInMB,omitempty"`
+}
+
+// Check that a cpu count and memory can be requested with this compute offering.
+// Custom offerings require both values, within the bounds the offering reports. Other offerings accept neither.
+func (computeOffering *ComputeOffering) ValidateCustomValues(cpuCount int, memoryInMB int) error {
+	if !computeOffering.Custom {
+		if cpuCount != 0 || memoryInMB != 0 {
+			return fmt.Errorf("Compute offering %s is not custom: cpu count and memory cannot be specified", computeOffering.Name)
+		}
+		return nil
+	}
+	if cpuCount == 0 || memoryInMB == 0 {
+		return fmt.Errorf("Compute offering %s is custom: cpu count and memory must be specified", computeOffering.Name)
+	}
+	if !inBounds(cpuCount, computeOffering.MinCpuCount, computeOffering.MaxCpuCount) {
+		return fmt.Errorf("Cpu count %d is not between %d and %d for compute offering %s", cpuCount, computeOffering.MinCpuCount, computeOffering.MaxCpuCount, computeOffering.Name)
+	}
+	if !inBounds(memoryInMB, computeOffering.MinMemoryInMB, computeOffering.MaxMemoryInMB) {
+		return fmt.Errorf("Memory %dMB is not between %dMB and %dMB for compute offering %s", memoryInMB, computeOffering.MinMemoryInMB, computeOffering.MaxMemoryInMB, computeOffering.Name)
+	}
+	return nil
+}
+
+// Select the offering with the least memory, then the least cpus, that provides at least the requested cpu count and memory.
+// A custom offering is sized exactly to the request, with its CpuCount and MemoryInMB set to the values to send.
+// Fixed offerings are preferred over custom offerings of the same size.
+func SelectSmallestComputeOffering(computeOfferings []ComputeOffering, cpuCount int, memoryInMB int) (*ComputeOffering, error) {
+	candidates := []ComputeOffering{}
+	for _, computeOffering := range computeOfferings {
+		if computeOffering.Custom {
+			sized := computeOffering
+			sized.CpuCount = maxInt(cpuCount, computeOffering.MinCpuCount)
+			sized.MemoryInMB = maxInt(memoryInMB, computeOffering.MinMemoryInMB)
+			if sized.ValidateCustomValues(sized.CpuCount, sized.MemoryInMB) == nil {
+				candidates = append(candidates, sized)
+			}
+		} else if computeOffering.CpuCount >= cpuCount && computeOffering.MemoryInMB >= memoryInMB {
+			candidates = append(candidates, computeOffering)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("No compute offering provides %d cpu(s) and %dMB of memory", cpuCount, memoryInMB)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].MemoryInMB != candidates[j].MemoryInMB {
+			return candidates[i].MemoryInMB < candidates[j].MemoryInMB
+		}
+		if candidates[i].CpuCount != candidates[j].CpuCount {
+			return candidates[i].CpuCount < candidates[j].CpuCount
+		}
+		return !candidates[i].Custom && candidates[j].Custom
+	})
+	return &candidates[0], nil
 }
 
 type ComputeOfferingService interface {
 	Get(id string) (*ComputeOffering, error)
 	List() ([]ComputeOffering, error)
 	ListWithOptions(options map[string]string) ([]ComputeOffering, error)
+	FindSmallest(cpuCount int, memoryInMB int) (*ComputeOffering, error)
 }
 
 type ComputeOfferingApi struct {
@@ -64,3 +125,24 @@ func (computeOfferingApi *ComputeOfferingApi) ListWithOptions(options map[string
 	}
 	return parseComputeOfferingList(data), nil
 }
+
+// Find the smallest compute offering of the current environment providing the requested cpu count and memory
+func (computeOfferingApi *ComputeOfferingApi) FindSmallest(cpuCount int, memoryInMB int) (*ComputeOffering, error) {
+	computeOfferings, err := computeOfferingApi.List()
+	if err != nil {
+		return nil, err
+	}
+	return SelectSmallestComputeOffering(computeOfferings, cpuCount, memoryInMB)
+}
+
+// Check the value is within the bounds, a bound of 0 is not reported by the offering and not enforced
+func inBounds(value int, min int, max int) bool {
+	return (min == 0 || value >= min) && (max == 0 || value <= max)
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -150,3 +150,86 @@ func TestListComputeOfferingReturnNilWithErrorIfError(t *testing.T) {
 	assert.Equal(t, mockError, err)
 
 }
+
+func TestValidateCustomValuesRefusesValuesForFixedOffering(t *testing.T) {
+	//given
+	computeOffering := ComputeOffering{Name: "1vCPU.1GB", CpuCount: 1, MemoryInMB: 1024}
+
+	//then
+	assert.NoError(t, computeOffering.ValidateCustomValues(0, 0))
+	assert.Error(t, computeOffering.ValidateCustomValues(2, 2048))
+}
+
+func TestValidateCustomValuesChecksRangesOfCustomOffering(t *testing.T) {
+	//given
+	computeOffering := ComputeOffering{Name: "custom", Custom: true, MaxCpuCount: 8}
+
+	//then
+	assert.NoError(t, computeOffering.ValidateCustomValues(8, 4096))
+	assert.Error(t, computeOffering.ValidateCustomValues(0, 4096))
+	assert.Error(t, computeOffering.ValidateCustomValues(9, 4096))
+	assert.NoError(t, computeOffering.ValidateCustomValues(2, 1048576))
+}
+
+func TestValidateCustomValuesEnforcesReportedMinimums(t *testing.T) {
+	//given
+	computeOffering := ComputeOffering{Name: "custom", Custom: true, MinCpuCount: 2, MinMemoryInMB: 2048}
+
+	//then
+	assert.NoError(t, computeOffering.ValidateCustomValues(64, 2048))
+	assert.Error(t, computeOffering.ValidateCustomValues(1, 2048))
+	assert.Error(t, computeOffering.ValidateCustomValues(2, 1024))
+}
+
+func TestSelectSmallestComputeOfferingPrefersSmallestFixedOffering(t *testing.T) {
+	//given
+	computeOfferings := []ComputeOffering{
+		{Id: "large", CpuCount: 4, MemoryInMB: 8192},
+		{Id: "medium", CpuCount: 2, MemoryInMB: 4096},
+		{Id: "small", CpuCount: 1, MemoryInMB: 1024},
+		{Id: "custom", Custom: true, MaxCpuCount: 2},
+	}
+
+	//when
+	computeOffering, err := SelectSmallestComputeOffering(computeOfferings, 2, 4096)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "medium", computeOffering.Id)
+}
+
+func TestSelectSmallestComputeOfferingSizesCustomOfferingToRequest(t *testing.T) {
+	//given
+	computeOfferings := []ComputeOffering{
+		{Id: "large", CpuCount: 4, MemoryInMB: 8192},
+		{Id: "custom", Custom: true},
+	}
+
+	//when
+	computeOffering, err := SelectSmallestComputeOffering(computeOfferings, 2, 3072)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, &ComputeOffering{Id: "custom", Custom: true, CpuCount: 2, MemoryInMB: 3072}, computeOffering)
+}
+
+func TestFindSmallestComputeOfferingReturnsErrorIfNoneIsLargeEnough(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	computeOfferingService := ComputeOfferingApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"small","cpuCount":1,"memoryInMB":1024}]`), nil)
+
+	//when
+	computeOffering, err := computeOfferingService.FindSmallest(2, 2048)
+
+	//then
+	assert.Nil(t, computeOffering)
+	assert.Error(t, err)
+}
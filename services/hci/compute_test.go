@@ -0,0 +1,82 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	TEST_MIXED_COMPUTE_LIST     = `[{"id":"vm1","name":"web","state":"Running","hypervisor":"KVM"},{"id":"bm1","name":"db","state":"Stopped","hypervisor":"BareMetal"}]`
+	TEST_BAREMETAL_COMPUTE_LIST = `[{"id":"bm1","name":"db","state":"Stopped","hypervisor":"BareMetal"}]`
+)
+
+func TestInstanceAndBaremetalListsReturnOnlyTheirKind(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	instanceService := InstanceApi{entityService: mockEntityService}
+	baremetalService := BaremetalApi{entityService: mockEntityService}
+	mockEntityService.EXPECT().List(map[string]string{}).Return([]byte(TEST_MIXED_COMPUTE_LIST), nil)
+	mockEntityService.EXPECT().List(map[string]string{LIST_OPTION_HYPERVISOR: HYPERVISOR_BAREMETAL}).Return([]byte(TEST_BAREMETAL_COMPUTE_LIST), nil)
+
+	//when
+	instances, instancesErr := instanceService.List()
+	baremetals, baremetalsErr := baremetalService.List()
+
+	//then
+	assert.NoError(t, instancesErr)
+	assert.NoError(t, baremetalsErr)
+	assert.Equal(t, []Instance{{Id: "vm1", Name: "web", State: "Running", Hypervisor: "KVM"}}, instances)
+	assert.Equal(t, []Baremetal{{Id: "bm1", Name: "db", State: "Stopped", Hypervisor: "BareMetal"}}, baremetals)
+}
+
+func TestInstanceListFiltersOutBaremetalServersWhenPaging(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	instanceService := InstanceApi{entityService: mockEntityService}
+	options := map[string]string{LIST_OPTION_PAGE: "1", LIST_OPTION_PAGE_SIZE: "2"}
+	mockEntityService.EXPECT().List(options).Return([]byte(TEST_MIXED_COMPUTE_LIST), nil)
+
+	//when
+	instances, err := instanceService.ListWithOptions(options)
+
+	//then
+	if assert.NoError(t, err) && assert.Len(t, instances, 1) {
+		assert.False(t, instances[0].IsBaremetal())
+	}
+}
+
+func TestListComputeLabelsEachResult(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	resources := Resources{
+		Instances:  &InstanceApi{entityService: mockEntityService},
+		Baremetals: &BaremetalApi{entityService: mockEntityService},
+	}
+	mockEntityService.EXPECT().List(map[string]string{}).Return([]byte(TEST_MIXED_COMPUTE_LIST), nil)
+	mockEntityService.EXPECT().List(map[string]string{LIST_OPTION_HYPERVISOR: HYPERVISOR_BAREMETAL}).Return([]byte(TEST_BAREMETAL_COMPUTE_LIST), nil)
+
+	//when
+	computeResources, err := resources.ListCompute()
+
+	//then
+	if assert.NoError(t, err) && assert.Len(t, computeResources, 2) {
+		assert.Equal(t, COMPUTE_KIND_VM, computeResources[0].Kind)
+		assert.Equal(t, "vm1", computeResources[0].Instance.Id)
+		assert.Nil(t, computeResources[0].Baremetal)
+		assert.Equal(t, COMPUTE_KIND_BAREMETAL, computeResources[1].Kind)
+		assert.Equal(t, "bm1", computeResources[1].Id)
+		assert.Equal(t, "Stopped", computeResources[1].Baremetal.State)
+	}
+}
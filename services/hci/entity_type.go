@@ -22,4 +22,7 @@ const (
 	ZONE_ENTITY_TYPE                   = "zones"
 	REMOTE_ACCESS_VPN_ENTITY_TYPE      = "remoteaccessvpns"
 	REMOTE_ACCESS_VPN_USER_ENTITY_TYPE = "vpnusers"
+	RECOVERY_POINT_ENTITY_TYPE         = "recoverypoints"
+	SNAPSHOT_ENTITY_TYPE               = "snapshots"
+	SNAPSHOT_POLICY_ENTITY_TYPE        = "snapshotpolicies"
 )
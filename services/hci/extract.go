@@ -0,0 +1,21 @@
+package hci
+
+import (
+	"encoding/json"
+)
+
+// Download URL of an extracted volume or template
+type ExtractResult struct {
+	Id    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Url   string `json:"url,omitempty"`
+	State string `json:"state,omitempty"`
+	// Date after which the URL no longer works
+	Expires string `json:"expires,omitempty"`
+}
+
+func parseExtractResult(data []byte) *ExtractResult {
+	result := ExtractResult{}
+	json.Unmarshal(data, &result)
+	return &result
+}
@@ -3,14 +3,23 @@ package hci
 import (
 	"encoding/json"
 	"strings"
+	"sync"
 
 	"github.com/hypertec-cloud/go-hci/api"
 	"github.com/hypertec-cloud/go-hci/services"
 )
 
+// States of an instance. See instance_state.go for the operations allowed in each state.
 const (
-	INSTANCE_STATE_RUNNING = "Running"
-	INSTANCE_STATE_STOPPED = "Stopped"
+	INSTANCE_STATE_STARTING  = "Starting"
+	INSTANCE_STATE_RUNNING   = "Running"
+	INSTANCE_STATE_STOPPING  = "Stopping"
+	INSTANCE_STATE_STOPPED   = "Stopped"
+	INSTANCE_STATE_MIGRATING = "Migrating"
+	INSTANCE_STATE_DESTROYED = "Destroyed"
+	INSTANCE_STATE_EXPUNGING = "Expunging"
+	INSTANCE_STATE_ERROR     = "Error"
+	INSTANCE_STATE_UNKNOWN   = "Unknown"
 )
 
 const (
@@ -20,6 +29,7 @@ const (
 	INSTANCE_RECOVER_OPERATION                 = "recover"
 	INSTANCE_PURGE_OPERATION                   = "purge"
 	INSTANCE_RESET_PASSWORD_OPERATION          = "resetPassword"
+	INSTANCE_GET_PASSWORD_OPERATION            = "getPassword"
 	INSTANCE_CREATE_RECOVERY_POINT_OPERATION   = "createRecoveryPoint"
 	INSTANCE_CHANGE_COMPUTE_OFFERING_OPERATION = "changeComputeOffering"
 	INSTANCE_CHANGE_NETWORK_OFFERING_OPERATION = "changeNetwork"
@@ -29,19 +39,22 @@ const (
 type Instance struct {
 	Id                       string        `json:"id,omitempty"`
 	Name                     string        `json:"name,omitempty"`
+	Description              string        `json:"description,omitempty"`
 	State                    string        `json:"state,omitempty"`
 	TemplateId               string        `json:"templateId,omitempty"`
 	TemplateName             string        `json:"templateName,omitempty"`
 	IsPasswordEnabled        bool          `json:"isPasswordEnabled,omitempty"`
 	IsSSHKeyEnabled          bool          `json:"isSshKeyEnabled,omitempty"`
 	Username                 string        `json:"username,omitempty"`
-	Password                 string        `json:"password,omitempty"`
+	Password                 SecretString  `json:"password,omitempty"`
+	EncryptedPassword        string        `json:"encryptedPassword,omitempty"`
 	SSHKeyName               string        `json:"sshKeyName,omitempty"`
 	ComputeOfferingId        string        `json:"computeOfferingId,omitempty"`
 	ComputeOfferingName      string        `json:"computeOfferingName,omitempty"`
 	NewComputeOfferingId     string        `json:"newComputeOfferingId,omitempty"`
 	CpuCount                 int           `json:"cpuCount,omitempty"`
 	MemoryInMB               int           `json:"memoryInMB,omitempty"`
+	Hypervisor               string        `json:"hypervisor,omitempty"`
 	ZoneId                   string        `json:"zoneId,omitempty"`
 	ZoneName                 string        `json:"zoneName,omitempty"`
 	ProjectId                string        `json:"projectId,omitempty"`
@@ -77,6 +90,11 @@ func (instance *Instance) IsRunning() bool {
 	return strings.EqualFold(instance.State, INSTANCE_STATE_RUNNING)
 }
 
+// Check if the instance is a baremetal server rather than a virtual instance
+func (instance *Instance) IsBaremetal() bool {
+	return isBaremetalHypervisor(instance.Hypervisor)
+}
+
 func (instance *Instance) IsStopped() bool {
 	return strings.EqualFold(instance.State, INSTANCE_STATE_STOPPED)
 }
@@ -86,6 +104,7 @@ type InstanceService interface {
 	List() ([]Instance, error)
 	ListWithOptions(options map[string]string) ([]Instance, error)
 	Create(Instance) (*Instance, error)
+	Update(Instance) (*Instance, error)
 	Destroy(id string, purge bool) (bool, error)
 	DestroyWithOptions(id string, options DestroyOptions) (bool, error)
 	Purge(id string) (bool, error)
@@ -98,16 +117,40 @@ type InstanceService interface {
 	ChangeComputeOffering(Instance) (bool, error)
 	ChangeNetwork(id string, newNetworkId string) (bool, error)
 	ResetPassword(id string) (string, error)
+	GetEncryptedPassword(id string) (string, error)
 	CreateRecoveryPoint(id string, recoveryPoint RecoveryPoint) (bool, error)
 }
 
+// Instance as sent on creation, with the actual password instead of the redacted one
+type instanceCreate struct {
+	Instance
+	Password string `json:"password,omitempty"`
+}
+
 type InstanceApi struct {
-	entityService services.EntityService
+	entityService          services.EntityService
+	computeOfferingService ComputeOfferingService
+	// Compute offerings fetched to check custom values, by id
+	computeOfferings      map[string]*ComputeOffering
+	computeOfferingsMutex sync.Mutex
+	// Refuse lifecycle operations the current state of the instance does not allow
+	guard bool
 }
 
 func NewInstanceService(apiClient api.ApiClient, serviceCode string, environmentName string) InstanceService {
 	return &InstanceApi{
-		entityService: services.NewEntityService(apiClient, serviceCode, environmentName, INSTANCE_ENTITY_TYPE),
+		entityService:          services.NewEntityService(apiClient, serviceCode, environmentName, INSTANCE_ENTITY_TYPE),
+		computeOfferingService: NewComputeOfferingService(apiClient, serviceCode, environmentName),
+	}
+}
+
+// Same as NewInstanceService, but the instance is fetched before start, stop, reboot, recover, purge and destroy
+// and an InvalidInstanceStateError is returned without calling the operation if its state does not allow it
+func NewGuardedInstanceService(apiClient api.ApiClient, serviceCode string, environmentName string) InstanceService {
+	return &InstanceApi{
+		entityService:          services.NewEntityService(apiClient, serviceCode, environmentName, INSTANCE_ENTITY_TYPE),
+		computeOfferingService: NewComputeOfferingService(apiClient, serviceCode, environmentName),
+		guard:                  true,
 	}
 }
 
@@ -138,17 +181,30 @@ func (instanceApi *InstanceApi) List() ([]Instance, error) {
 }
 
 // List all instances for the current environment. Can use options to do sorting and paging.
+// Baremetal servers are filtered out, see the Baremetals service to list them. The server cannot exclude them,
+// so a page may hold fewer instances than the page size when it contains baremetal servers.
 func (instanceApi *InstanceApi) ListWithOptions(options map[string]string) ([]Instance, error) {
 	data, err := instanceApi.entityService.List(options)
 	if err != nil {
 		return nil, err
 	}
-	return parseInstanceList(data), nil
+	instances := parseInstanceList(data)
+	virtualInstances := []Instance{}
+	for _, instance := range instances {
+		if !instance.IsBaremetal() {
+			virtualInstances = append(virtualInstances, instance)
+		}
+	}
+	return virtualInstances, nil
 }
 
 // Create an instance in the current environment
+// The cpu count and memory must be set for a custom compute offering only, they are validated against it
 func (instanceApi *InstanceApi) Create(instance Instance) (*Instance, error) {
-	send, merr := json.Marshal(instance)
+	if err := instanceApi.validateCustomValues(instance.ComputeOfferingId, instance); err != nil {
+		return nil, err
+	}
+	send, merr := json.Marshal(instanceCreate{Instance: instance, Password: instance.Password.Reveal()})
 	if merr != nil {
 		return nil, merr
 	}
@@ -159,9 +215,31 @@ func (instanceApi *InstanceApi) Create(instance Instance) (*Instance, error) {
 	return parseInstance(body), nil
 }
 
+// Update the mutable attributes of the instance with the specified id in the current environment.
+// Only the name, description and user data are sent; empty values are left unchanged.
+// Note: A new user data is only applied by cloud-init on the next boot
+func (instanceApi *InstanceApi) Update(instance Instance) (*Instance, error) {
+	send, merr := json.Marshal(Instance{
+		Name:        instance.Name,
+		Description: instance.Description,
+		UserData:    instance.UserData,
+	})
+	if merr != nil {
+		return nil, merr
+	}
+	body, err := instanceApi.entityService.Update(instance.Id, send, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseInstance(body), nil
+}
+
 // Destroy an instance with specified id in the current environment
 // Set the purge flag to true if you want to purge immediately
 func (instanceApi *InstanceApi) Destroy(id string, purge bool) (bool, error) {
+	if err := instanceApi.checkState(id, instanceDestroyOperation); err != nil {
+		return false, err
+	}
 	send, merr := json.Marshal(DestroyOptions{
 		PurgeImmediately: purge,
 	})
@@ -175,6 +253,9 @@ func (instanceApi *InstanceApi) Destroy(id string, purge bool) (bool, error) {
 // Destroy an instance with specified id in the current environment
 // Set the purge flag to true if you want to purge immediately
 func (instanceApi *InstanceApi) DestroyWithOptions(id string, options DestroyOptions) (bool, error) {
+	if err := instanceApi.checkState(id, instanceDestroyOperation); err != nil {
+		return false, err
+	}
 	send, merr := json.Marshal(options)
 	if merr != nil {
 		return false, merr
@@ -186,6 +267,9 @@ func (instanceApi *InstanceApi) DestroyWithOptions(id string, options DestroyOpt
 // Purge an instance with the specified id in the current environment
 // The instance must be in the Destroyed state. To destroy and purge an instance, see the Destroy method
 func (instanceApi *InstanceApi) Purge(id string) (bool, error) {
+	if err := instanceApi.checkState(id, INSTANCE_PURGE_OPERATION); err != nil {
+		return false, err
+	}
 	_, err := instanceApi.entityService.Execute(id, INSTANCE_PURGE_OPERATION, []byte{}, map[string]string{})
 	return err == nil, err
 }
@@ -193,6 +277,9 @@ func (instanceApi *InstanceApi) Purge(id string) (bool, error) {
 // Recover a destroyed instance with the specified id in the current environment
 // Note: Cannot recover instances that have been purged
 func (instanceApi *InstanceApi) Recover(id string) (bool, error) {
+	if err := instanceApi.checkState(id, INSTANCE_RECOVER_OPERATION); err != nil {
+		return false, err
+	}
 	_, err := instanceApi.entityService.Execute(id, INSTANCE_RECOVER_OPERATION, []byte{}, map[string]string{})
 	return err == nil, err
 }
@@ -211,12 +298,18 @@ func (instanceApi *InstanceApi) Exists(id string) (bool, error) {
 
 // Start a stopped instance with specified id exists in the current environment
 func (instanceApi *InstanceApi) Start(id string) (bool, error) {
+	if err := instanceApi.checkState(id, INSTANCE_START_OPERATION); err != nil {
+		return false, err
+	}
 	_, err := instanceApi.entityService.Execute(id, INSTANCE_START_OPERATION, []byte{}, map[string]string{})
 	return err == nil, err
 }
 
 // Stop a running instance with specified id exists in the current environment
 func (instanceApi *InstanceApi) Stop(id string) (bool, error) {
+	if err := instanceApi.checkState(id, INSTANCE_STOP_OPERATION); err != nil {
+		return false, err
+	}
 	_, err := instanceApi.entityService.Execute(id, INSTANCE_STOP_OPERATION, []byte{}, map[string]string{})
 	return err == nil, err
 }
@@ -236,13 +329,24 @@ func (instanceApi *InstanceApi) AssociateSSHKey(id string, sshKeyName string) (b
 
 // Reboot a running instance with specified id exists in the current environment
 func (instanceApi *InstanceApi) Reboot(id string) (bool, error) {
+	if err := instanceApi.checkState(id, INSTANCE_REBOOT_OPERATION); err != nil {
+		return false, err
+	}
 	_, err := instanceApi.entityService.Execute(id, INSTANCE_REBOOT_OPERATION, []byte{}, map[string]string{})
 	return err == nil, err
 }
 
 // Change the compute offering of the instance with the specified id exists in the current environment
+// The cpu count and memory must be set for a custom compute offering only, they are validated against it
 // Note: This will reboot your instance if running
 func (instanceApi *InstanceApi) ChangeComputeOffering(instance Instance) (bool, error) {
+	computeOfferingId := instance.NewComputeOfferingId
+	if computeOfferingId == "" {
+		computeOfferingId = instance.ComputeOfferingId
+	}
+	if err := instanceApi.validateCustomValues(computeOfferingId, instance); err != nil {
+		return false, err
+	}
 	send, merr := json.Marshal(instance)
 	if merr != nil {
 		return false, merr
@@ -258,7 +362,18 @@ func (instanceApi *InstanceApi) ResetPassword(id string) (string, error) {
 		return "", err
 	}
 	instance := parseInstance(body)
-	return instance.Password, nil
+	return instance.Password.Reveal(), nil
+}
+
+// Get the password of the instance with the specified id, encrypted with the public key of its SSH key.
+// See DecryptInstancePassword to decrypt it with the matching private key.
+func (instanceApi *InstanceApi) GetEncryptedPassword(id string) (string, error) {
+	body, err := instanceApi.entityService.Execute(id, INSTANCE_GET_PASSWORD_OPERATION, []byte{}, map[string]string{})
+	if err != nil {
+		return "", err
+	}
+	instance := parseInstance(body)
+	return instance.EncryptedPassword, nil
 }
 
 // Change the network of the instance with the specified id
@@ -283,3 +398,33 @@ func (instanceApi *InstanceApi) CreateRecoveryPoint(id string, recoveryPoint Rec
 	_, err := instanceApi.entityService.Execute(id, INSTANCE_CREATE_RECOVERY_POINT_OPERATION, send, map[string]string{})
 	return err == nil, err
 }
+
+// Validate the cpu count and memory of the instance against the compute offering.
+// Compute offerings are fetched once per service.
+func (instanceApi *InstanceApi) validateCustomValues(computeOfferingId string, instance Instance) error {
+	if computeOfferingId == "" {
+		return nil
+	}
+	computeOffering, err := instanceApi.getComputeOffering(computeOfferingId)
+	if err != nil {
+		return err
+	}
+	return computeOffering.ValidateCustomValues(instance.CpuCount, instance.MemoryInMB)
+}
+
+func (instanceApi *InstanceApi) getComputeOffering(id string) (*ComputeOffering, error) {
+	instanceApi.computeOfferingsMutex.Lock()
+	defer instanceApi.computeOfferingsMutex.Unlock()
+	if computeOffering, ok := instanceApi.computeOfferings[id]; ok {
+		return computeOffering, nil
+	}
+	computeOffering, err := instanceApi.computeOfferingService.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if instanceApi.computeOfferings == nil {
+		instanceApi.computeOfferings = map[string]*ComputeOffering{}
+	}
+	instanceApi.computeOfferings[id] = computeOffering
+	return computeOffering, nil
+}
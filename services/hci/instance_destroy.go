@@ -0,0 +1,193 @@
+package hci
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Everything tied to an instance that ExecuteDestroy tears down with it.
+// Remove items from the plan to keep them: a data volume removed from DataVolumes stays detached,
+// a public IP removed from StaticNatPublicIps is kept without static NAT.
+type DestroyPlan struct {
+	Instance            Instance
+	DataVolumes         []Volume
+	StaticNatPublicIps  []PublicIp
+	PortForwardingRules []PortForwardingRule
+	LoadBalancerRules   []LoadBalancerRule
+	RecoveryPoints      []RecoveryPoint
+	PurgeImmediately    bool
+}
+
+// Describe the plan, one line per resource, in the order the resources are torn down
+func (plan DestroyPlan) String() string {
+	lines := []string{"Destroy instance " + plan.Instance.Name + " (" + plan.Instance.Id + ")"}
+	for _, lbr := range plan.LoadBalancerRules {
+		lines = append(lines, "  remove from load balancer rule "+lbr.Name+" ("+lbr.Id+")")
+	}
+	for _, pfr := range plan.PortForwardingRules {
+		lines = append(lines, "  delete port forwarding rule "+pfr.PublicIp+":"+pfr.PublicPortStart+" ("+pfr.Id+")")
+	}
+	for _, recoveryPoint := range plan.RecoveryPoints {
+		lines = append(lines, "  delete recovery point "+recoveryPoint.Name+" ("+recoveryPoint.Id+")")
+	}
+	for _, publicIp := range plan.StaticNatPublicIps {
+		lines = append(lines, "  release public IP "+publicIp.IpAddress+" ("+publicIp.Id+")")
+	}
+	for _, volume := range plan.DataVolumes {
+		lines = append(lines, "  delete volume "+volume.Name+" ("+volume.Id+")")
+	}
+	if plan.PurgeImmediately {
+		lines = append(lines, "  purge immediately")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Find the resources tied to the instance with the specified id in the current environment.
+// Nothing is modified until the plan is passed to ExecuteDestroy.
+func (resources Resources) PlanDestroy(id string) (*DestroyPlan, error) {
+	instance, err := resources.Instances.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := resources.Volumes.List()
+	if err != nil {
+		return nil, err
+	}
+	publicIps, err := resources.PublicIps.List()
+	if err != nil {
+		return nil, err
+	}
+	pfrs, err := resources.PortForwardingRules.List()
+	if err != nil {
+		return nil, err
+	}
+	lbrs, err := resources.LoadBalancerRules.List()
+	if err != nil {
+		return nil, err
+	}
+	recoveryPoints, err := resources.RecoveryPoints.ListOfInstance(id)
+	if err != nil {
+		return nil, err
+	}
+	dataVolumes := []Volume{}
+	for _, volume := range volumesOfInstance(volumes, id) {
+		if strings.EqualFold(volume.Type, VOLUME_TYPE_DATA) {
+			dataVolumes = append(dataVolumes, volume)
+		}
+	}
+	return &DestroyPlan{
+		Instance:            *instance,
+		DataVolumes:         dataVolumes,
+		StaticNatPublicIps:  staticNatPublicIpsOfInstance(publicIps, id),
+		PortForwardingRules: portForwardingRulesOfInstance(pfrs, id),
+		LoadBalancerRules:   loadBalancerRulesOfInstance(lbrs, id),
+		RecoveryPoints:      recoveryPoints,
+	}, nil
+}
+
+// Tear down the plan: remove the instance from its load balancer rules, delete its port forwarding rules
+// and recovery points, disable static NAT, then destroy the instance, releasing its public IPs and deleting its data volumes.
+// Stops at the first error; resources already torn down are not restored.
+func (resources Resources) ExecuteDestroy(plan DestroyPlan) (bool, error) {
+	instanceId := plan.Instance.Id
+	for _, lbr := range plan.LoadBalancerRules {
+		remaining := []string{}
+		for _, id := range lbr.InstanceIds {
+			if id != instanceId {
+				remaining = append(remaining, id)
+			}
+		}
+		if err := resources.LoadBalancerRules.SetLoadBalancerRuleInstances(lbr.Id, remaining); err != nil {
+			return false, fmt.Errorf("Failed to remove instance %s from load balancer rule %s: %s", instanceId, lbr.Id, err)
+		}
+	}
+	for _, pfr := range plan.PortForwardingRules {
+		if _, err := resources.PortForwardingRules.Delete(pfr.Id); err != nil {
+			return false, fmt.Errorf("Failed to delete port forwarding rule %s: %s", pfr.Id, err)
+		}
+	}
+	for _, recoveryPoint := range plan.RecoveryPoints {
+		if _, err := resources.RecoveryPoints.Delete(recoveryPoint.Id); err != nil {
+			return false, fmt.Errorf("Failed to delete recovery point %s: %s", recoveryPoint.Id, err)
+		}
+	}
+	publicIpIds := []string{}
+	for _, publicIp := range plan.StaticNatPublicIps {
+		if _, err := resources.PublicIps.DisableStaticNat(publicIp.Id); err != nil {
+			return false, fmt.Errorf("Failed to disable static NAT on public IP %s: %s", publicIp.Id, err)
+		}
+		publicIpIds = append(publicIpIds, publicIp.Id)
+	}
+	volumeIds := []string{}
+	for _, volume := range plan.DataVolumes {
+		volumeIds = append(volumeIds, volume.Id)
+	}
+	return resources.Instances.DestroyWithOptions(instanceId, DestroyOptions{
+		PurgeImmediately:     plan.PurgeImmediately,
+		PublicIpIdsToRelease: publicIpIds,
+		VolumeIdsToDelete:    volumeIds,
+	})
+}
+
+func volumesOfInstance(volumes []Volume, instanceId string) []Volume {
+	filtered := []Volume{}
+	for _, volume := range volumes {
+		if volume.InstanceId == instanceId {
+			filtered = append(filtered, volume)
+		}
+	}
+	return filtered
+}
+
+func publicIpsOfInstance(publicIps []PublicIp, instanceId string) []PublicIp {
+	filtered := []PublicIp{}
+	for _, publicIp := range publicIps {
+		if publicIp.InstanceId == instanceId {
+			filtered = append(filtered, publicIp)
+		}
+	}
+	return filtered
+}
+
+// Public IPs of the instance that have static NAT enabled, other public IPs are left as is
+func staticNatPublicIpsOfInstance(publicIps []PublicIp, instanceId string) []PublicIp {
+	filtered := []PublicIp{}
+	for _, publicIp := range publicIpsOfInstance(publicIps, instanceId) {
+		for _, purpose := range publicIp.Purposes {
+			if strings.EqualFold(purpose, PUBLIC_IP_PURPOSE_STATIC_NAT) {
+				filtered = append(filtered, publicIp)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func portForwardingRulesOfInstance(pfrs []PortForwardingRule, instanceId string) []PortForwardingRule {
+	filtered := []PortForwardingRule{}
+	for _, pfr := range pfrs {
+		if pfr.InstanceId == instanceId {
+			filtered = append(filtered, pfr)
+		}
+	}
+	return filtered
+}
+
+func loadBalancerRulesOfInstance(lbrs []LoadBalancerRule, instanceId string) []LoadBalancerRule {
+	filtered := []LoadBalancerRule{}
+	for _, lbr := range lbrs {
+		if containsId(lbr.InstanceIds, instanceId) {
+			filtered = append(filtered, lbr)
+		}
+	}
+	return filtered
+}
+
+func containsId(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package hci
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanDestroyFindsResourcesTiedToInstance(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","name":"web-01"}`), nil)
+	mockServices.volumes.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"v1","type":"OS","instanceId":"i1"},{"id":"v2","type":"DATA","instanceId":"i1"},{"id":"v3","type":"DATA","instanceId":"i2"}]`), nil)
+	mockServices.publicIps.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"ip1","instanceId":"i1","purposes":["STATIC_NAT"]},{"id":"ip2"},{"id":"ip3","instanceId":"i1","purposes":["PORT_FORWARDING"]}]`), nil)
+	mockServices.pfrs.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"pfr1","instanceId":"i2"},{"id":"pfr2","instanceId":"i1"}]`), nil)
+	mockServices.lbrs.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"lbr1","instanceIds":["i2","i1"]},{"id":"lbr2","instanceIds":["i2"]}]`), nil)
+	mockServices.recoveryPoints.EXPECT().List(map[string]string{"instanceId": "i1"}).Return([]byte(`[{"id":"rp1"}]`), nil)
+
+	//when
+	plan, err := resources.PlanDestroy("i1")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, DestroyPlan{
+			Instance:            Instance{Id: "i1", Name: "web-01"},
+			DataVolumes:         []Volume{{Id: "v2", Type: "DATA", InstanceId: "i1"}},
+			StaticNatPublicIps:  []PublicIp{{Id: "ip1", InstanceId: "i1", Purposes: []string{PUBLIC_IP_PURPOSE_STATIC_NAT}}},
+			PortForwardingRules: []PortForwardingRule{{Id: "pfr2", InstanceId: "i1"}},
+			LoadBalancerRules:   []LoadBalancerRule{{Id: "lbr1", InstanceIds: []string{"i2", "i1"}}},
+			RecoveryPoints:      []RecoveryPoint{{Id: "rp1"}},
+		}, *plan)
+	}
+}
+
+func TestPlanDestroyReturnsErrorIfInstanceIsNotFound(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockError := mocks.MockError{Message: "some_get_error"}
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return(nil, mockError)
+
+	//when
+	plan, err := resources.PlanDestroy("i1")
+
+	//then
+	assert.Nil(t, plan)
+	assert.Equal(t, mockError, err)
+}
+
+func TestExecuteDestroyTearsDownPlanInOrder(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	plan := DestroyPlan{
+		Instance:            Instance{Id: "i1"},
+		DataVolumes:         []Volume{{Id: "v2"}},
+		StaticNatPublicIps:  []PublicIp{{Id: "ip1"}},
+		PortForwardingRules: []PortForwardingRule{{Id: "pfr2"}},
+		LoadBalancerRules:   []LoadBalancerRule{{Id: "lbr1", InstanceIds: []string{"i2", "i1"}}},
+		RecoveryPoints:      []RecoveryPoint{{Id: "rp1"}},
+		PurgeImmediately:    true,
+	}
+	expectedLbrBody, _ := json.Marshal(LoadBalancerRule{Id: "lbr1", InstanceIds: []string{"i2"}})
+	expectedDestroyBody, _ := json.Marshal(DestroyOptions{PurgeImmediately: true, PublicIpIdsToRelease: []string{"ip1"}, VolumeIdsToDelete: []string{"v2"}})
+	gomock.InOrder(
+		mockServices.lbrs.EXPECT().Execute("lbr1", UPDATE_INSTANCES, expectedLbrBody, gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.pfrs.EXPECT().Delete("pfr2", gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.recoveryPoints.EXPECT().Delete("rp1", gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.publicIps.EXPECT().Execute("ip1", PUBLIC_IP_DISABLE_STATIC_NAT_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.instances.EXPECT().Delete("i1", expectedDestroyBody, gomock.Any()).Return([]byte(`{}`), nil),
+	)
+
+	//when
+	success, err := resources.ExecuteDestroy(plan)
+
+	//then
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
+
+func TestExecuteDestroyStopsAtFirstError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	plan := DestroyPlan{
+		Instance:            Instance{Id: "i1"},
+		PortForwardingRules: []PortForwardingRule{{Id: "pfr2"}},
+		RecoveryPoints:      []RecoveryPoint{{Id: "rp1"}},
+	}
+	mockServices.pfrs.EXPECT().Delete("pfr2", gomock.Any(), gomock.Any()).Return(nil, mocks.MockError{Message: "some_delete_error"})
+
+	//when
+	success, err := resources.ExecuteDestroy(plan)
+
+	//then
+	assert.Error(t, err)
+	assert.False(t, success)
+}
+
+func TestExecuteDestroyRemovesLastInstanceOfLoadBalancerRule(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	plan := DestroyPlan{
+		Instance:          Instance{Id: "i1"},
+		LoadBalancerRules: []LoadBalancerRule{{Id: "lbr1", InstanceIds: []string{"i1"}}},
+	}
+	gomock.InOrder(
+		mockServices.lbrs.EXPECT().Execute("lbr1", UPDATE_INSTANCES, []byte(`{"id":"lbr1","instanceIds":[]}`), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.instances.EXPECT().Delete("i1", gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil),
+	)
+
+	//when
+	success, err := resources.ExecuteDestroy(plan)
+
+	//then
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
@@ -0,0 +1,126 @@
+package hci
+
+import (
+	"sort"
+)
+
+// An instance with the resources it uses resolved
+type InstanceDetails struct {
+	Instance            Instance
+	Volumes             []Volume
+	PublicIps           []PublicIp
+	PortForwardingRules []PortForwardingRule
+	LoadBalancerRules   []LoadBalancerRule
+	// nil if the network could not be fetched
+	Network *Network
+	// nil if the instance is not in a VPC or the VPC could not be fetched
+	Vpc            *Vpc
+	AffinityGroups []AffinityGroup
+}
+
+// Returned with partial InstanceDetails when some of the resources of the instance could not be fetched.
+// Failures are keyed by the kind of resource (ex: "volumes", "network").
+type InstanceDetailsError struct {
+	InstanceId string
+	Failures   map[string]error
+}
+
+func (e InstanceDetailsError) Error() string {
+	kinds := []string{}
+	for kind := range e.Failures {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	errorStr := "Failed to get details of instance " + e.InstanceId + "\n"
+	for _, kind := range kinds {
+		errorStr += kind + ": " + e.Failures[kind].Error() + "\n"
+	}
+	return errorStr
+}
+
+// Get the instance with the specified id in the current environment with its volumes, public IPs, port forwarding rules,
+// load balancer rules, network, VPC and affinity groups. The resources are fetched concurrently.
+// If only some resources could not be fetched, the partial details are returned with an InstanceDetailsError.
+func (resources Resources) GetInstanceDetails(id string) (*InstanceDetails, error) {
+	instance, err := resources.Instances.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	details := InstanceDetails{
+		Instance:            *instance,
+		Volumes:             []Volume{},
+		PublicIps:           []PublicIp{},
+		PortForwardingRules: []PortForwardingRule{},
+		LoadBalancerRules:   []LoadBalancerRule{},
+		AffinityGroups:      []AffinityGroup{},
+	}
+	kinds := []string{"volumes", "publicIps", "portForwardingRules", "loadBalancerRules", "network", "vpc", "affinityGroups"}
+	fetches := map[string]func() error{
+		"volumes": func() error {
+			volumes, err := resources.Volumes.List()
+			details.Volumes = volumesOfInstance(volumes, id)
+			return err
+		},
+		"publicIps": func() error {
+			publicIps, err := resources.PublicIps.List()
+			details.PublicIps = publicIpsOfInstance(publicIps, id)
+			return err
+		},
+		"portForwardingRules": func() error {
+			pfrs, err := resources.PortForwardingRules.List()
+			details.PortForwardingRules = portForwardingRulesOfInstance(pfrs, id)
+			return err
+		},
+		"loadBalancerRules": func() error {
+			lbrs, err := resources.LoadBalancerRules.List()
+			details.LoadBalancerRules = loadBalancerRulesOfInstance(lbrs, id)
+			return err
+		},
+		"network": func() error {
+			if instance.NetworkId == "" {
+				return nil
+			}
+			network, err := resources.Networks.Get(instance.NetworkId)
+			details.Network = network
+			return err
+		},
+		"vpc": func() error {
+			if instance.VpcId == "" {
+				return nil
+			}
+			vpc, err := resources.Vpcs.Get(instance.VpcId)
+			details.Vpc = vpc
+			return err
+		},
+		"affinityGroups": func() error {
+			affinityGroups, err := resources.AffinityGroups.List()
+			details.AffinityGroups = affinityGroupsOfInstance(affinityGroups, *instance)
+			return err
+		},
+	}
+	errors := make([]error, len(kinds))
+	runConcurrently(len(kinds), len(kinds), func(i int) {
+		errors[i] = fetches[kinds[i]]()
+	})
+
+	failures := map[string]error{}
+	for i, err := range errors {
+		if err != nil {
+			failures[kinds[i]] = err
+		}
+	}
+	if len(failures) > 0 {
+		return &details, InstanceDetailsError{InstanceId: id, Failures: failures}
+	}
+	return &details, nil
+}
+
+func affinityGroupsOfInstance(affinityGroups []AffinityGroup, instance Instance) []AffinityGroup {
+	filtered := []AffinityGroup{}
+	for _, affinityGroup := range affinityGroups {
+		if containsId(instance.AffinityGroupIds, affinityGroup.Id) || containsId(affinityGroup.InstanceIds, instance.Id) {
+			filtered = append(filtered, affinityGroup)
+		}
+	}
+	return filtered
+}
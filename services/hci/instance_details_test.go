@@ -0,0 +1,79 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInstanceDetailsResolvesResourcesOfInstance(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockNetworks := services_mocks.NewMockEntityService(ctrl)
+	mockVpcs := services_mocks.NewMockEntityService(ctrl)
+	mockAffinityGroups := services_mocks.NewMockEntityService(ctrl)
+	resources.Networks = &NetworkApi{entityService: mockNetworks}
+	resources.Vpcs = &VpcApi{entityService: mockVpcs}
+	resources.AffinityGroups = &AffinityGroupApi{entityService: mockAffinityGroups}
+
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","networkId":"n1","vpcId":"vpc1"}`), nil)
+	mockServices.volumes.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"v1","instanceId":"i1"},{"id":"v2","instanceId":"i2"}]`), nil)
+	mockServices.publicIps.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"ip1","instanceId":"i1"}]`), nil)
+	mockServices.pfrs.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"pfr1","instanceId":"i1"}]`), nil)
+	mockServices.lbrs.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"lbr1","instanceIds":["i1"]}]`), nil)
+	mockNetworks.EXPECT().Get("n1", gomock.Any()).Return([]byte(`{"id":"n1"}`), nil)
+	mockVpcs.EXPECT().Get("vpc1", gomock.Any()).Return([]byte(`{"id":"vpc1"}`), nil)
+	mockAffinityGroups.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"ag1","instanceIds":["i1"]},{"id":"ag2","instanceIds":["i2"]}]`), nil)
+
+	//when
+	details, err := resources.GetInstanceDetails("i1")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, InstanceDetails{
+			Instance:            Instance{Id: "i1", NetworkId: "n1", VpcId: "vpc1"},
+			Volumes:             []Volume{{Id: "v1", InstanceId: "i1"}},
+			PublicIps:           []PublicIp{{Id: "ip1", InstanceId: "i1"}},
+			PortForwardingRules: []PortForwardingRule{{Id: "pfr1", InstanceId: "i1"}},
+			LoadBalancerRules:   []LoadBalancerRule{{Id: "lbr1", InstanceIds: []string{"i1"}}},
+			Network:             &Network{Id: "n1"},
+			Vpc:                 &Vpc{Id: "vpc1"},
+			AffinityGroups:      []AffinityGroup{{Id: "ag1", InstanceIds: []string{"i1"}}},
+		}, *details)
+	}
+}
+
+func TestGetInstanceDetailsReturnsPartialDetailsOnFailure(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockAffinityGroups := services_mocks.NewMockEntityService(ctrl)
+	resources.AffinityGroups = &AffinityGroupApi{entityService: mockAffinityGroups}
+
+	mockError := mocks.MockError{Message: "some_list_error"}
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1"}`), nil)
+	mockServices.volumes.EXPECT().List(gomock.Any()).Return(nil, mockError)
+	mockServices.publicIps.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"ip1","instanceId":"i1"}]`), nil)
+	mockServices.pfrs.EXPECT().List(gomock.Any()).Return([]byte(`[]`), nil)
+	mockServices.lbrs.EXPECT().List(gomock.Any()).Return([]byte(`[]`), nil)
+	mockAffinityGroups.EXPECT().List(gomock.Any()).Return([]byte(`[]`), nil)
+
+	//when
+	details, err := resources.GetInstanceDetails("i1")
+
+	//then
+	assert.Equal(t, InstanceDetailsError{InstanceId: "i1", Failures: map[string]error{"volumes": mockError}}, err)
+	if assert.NotNil(t, details) {
+		assert.Empty(t, details.Volumes)
+		assert.Equal(t, []PublicIp{{Id: "ip1", InstanceId: "i1"}}, details.PublicIps)
+		assert.Nil(t, details.Network)
+	}
+}
@@ -0,0 +1,176 @@
+package hci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Maximum number of instances created at the same time by LaunchFleet
+const DEFAULT_FLEET_CONCURRENCY = 4
+
+// Where members of a fleet are placed. Empty fields keep the value of the launch spec.
+type FleetPlacement struct {
+	ZoneId           string
+	NetworkId        string
+	AffinityGroupIds []string
+}
+
+type FleetRequest struct {
+	// Launch spec shared by all the members. The name of each member comes from NamePattern.
+	Spec Instance
+	// Number of members to create
+	Count int
+	// Minimum number of members for the fleet to be kept. If fewer members are created, the ones created are destroyed.
+	// Defaults to Count.
+	MinCount int
+	// fmt pattern receiving the index of each member (ex: "web-%02d")
+	NamePattern string
+	// Index of the first member. Defaults to 1.
+	FirstIndex int
+	// Members are spread over the placements in round robin
+	Placements []FleetPlacement
+	// Maximum number of instances created at the same time. Defaults to DEFAULT_FLEET_CONCURRENCY.
+	Concurrency int
+}
+
+type FleetFailure struct {
+	Name  string
+	Error error
+}
+
+// The members of a launched fleet, in index order, and the members that could not be created
+type Fleet struct {
+	Instances []Instance
+	Failures  []FleetFailure
+}
+
+// Returned when a fleet could not reach its minimum size. The members that were created have been destroyed,
+// except the ones listed in RollbackFailures.
+type FleetError struct {
+	Count            int
+	MinCount         int
+	Created          int
+	Failures         []FleetFailure
+	RollbackFailures []FleetFailure
+}
+
+func (e FleetError) Error() string {
+	errorStr := "Fleet reached " + strconv.Itoa(e.Created) + " of " + strconv.Itoa(e.Count) + " instances, minimum is " + strconv.Itoa(e.MinCount) + "\n"
+	for _, failure := range e.Failures {
+		errorStr += "Failed to create " + failure.Name + ": " + failure.Error.Error() + "\n"
+	}
+	for _, failure := range e.RollbackFailures {
+		errorStr += "Failed to destroy " + failure.Name + ": " + failure.Error.Error() + "\n"
+	}
+	return errorStr
+}
+
+// Build the instance to create for each member of the fleet
+func (request FleetRequest) memberSpecs() ([]Instance, error) {
+	if request.Count < 1 {
+		return nil, fmt.Errorf("Fleet count must be positive")
+	}
+	if request.MinCount > request.Count || request.MinCount < 0 {
+		return nil, fmt.Errorf("Fleet minimum count must be between 0 and %d", request.Count)
+	}
+	if !strings.Contains(request.NamePattern, "%") {
+		return nil, fmt.Errorf("Fleet name pattern %s must contain a verb for the member index (ex: web-%%02d)", request.NamePattern)
+	}
+	firstIndex := request.FirstIndex
+	if firstIndex == 0 {
+		firstIndex = 1
+	}
+	specs := make([]Instance, request.Count)
+	for i := range specs {
+		spec := request.Spec
+		spec.Name = fmt.Sprintf(request.NamePattern, firstIndex+i)
+		if len(request.Placements) > 0 {
+			placement := request.Placements[i%len(request.Placements)]
+			if placement.ZoneId != "" {
+				spec.ZoneId = placement.ZoneId
+			}
+			if placement.NetworkId != "" {
+				spec.NetworkId = placement.NetworkId
+			}
+			if placement.AffinityGroupIds != nil {
+				spec.AffinityGroupIds = placement.AffinityGroupIds
+			}
+		}
+		specs[i] = spec
+	}
+	return specs, nil
+}
+
+// Create Count identical instances concurrently in the current environment.
+// If fewer than MinCount instances could be created, the created instances are purged and a FleetError is returned.
+func (resources Resources) LaunchFleet(request FleetRequest) (*Fleet, error) {
+	specs, err := request.memberSpecs()
+	if err != nil {
+		return nil, err
+	}
+	minCount := request.MinCount
+	if minCount == 0 {
+		minCount = request.Count
+	}
+
+	created := make([]*Instance, len(specs))
+	errors := make([]error, len(specs))
+	runConcurrently(len(specs), request.Concurrency, func(i int) {
+		created[i], errors[i] = resources.Instances.Create(specs[i])
+	})
+
+	fleet := Fleet{Instances: []Instance{}, Failures: []FleetFailure{}}
+	for i, spec := range specs {
+		if errors[i] != nil {
+			fleet.Failures = append(fleet.Failures, FleetFailure{Name: spec.Name, Error: errors[i]})
+			continue
+		}
+		instance := *created[i]
+		if instance.Name == "" {
+			instance.Name = spec.Name
+		}
+		fleet.Instances = append(fleet.Instances, instance)
+	}
+	if len(fleet.Instances) >= minCount {
+		return &fleet, nil
+	}
+
+	rollbackErrors := make([]error, len(fleet.Instances))
+	runConcurrently(len(fleet.Instances), request.Concurrency, func(i int) {
+		_, rollbackErrors[i] = resources.Instances.Destroy(fleet.Instances[i].Id, true)
+	})
+	fleetError := FleetError{
+		Count:            request.Count,
+		MinCount:         minCount,
+		Created:          len(fleet.Instances),
+		Failures:         fleet.Failures,
+		RollbackFailures: []FleetFailure{},
+	}
+	for i, err := range rollbackErrors {
+		if err != nil {
+			fleetError.RollbackFailures = append(fleetError.RollbackFailures, FleetFailure{Name: fleet.Instances[i].Name, Error: err})
+		}
+	}
+	return nil, fleetError
+}
+
+// Call task for every index from 0 to count-1, with at most concurrency calls at the same time
+func runConcurrently(count int, concurrency int, task func(i int)) {
+	if concurrency < 1 {
+		concurrency = DEFAULT_FLEET_CONCURRENCY
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			task(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,138 @@
+package hci
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaunchFleetCreatesNamedInstancesSpreadOverPlacements(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
+	resources := Resources{Instances: &InstanceApi{
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
+	}}
+
+	mockComputeOfferingEntityService.EXPECT().Get("offering", gomock.Any()).Return([]byte(`{"id":"offering","cpuCount":1,"memoryInMB":1024}`), nil).Times(1)
+	var mutex sync.Mutex
+	sent := map[string]Instance{}
+	mockEntityService.EXPECT().Create(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(func(body []byte, options map[string]string) ([]byte, error) {
+		instance := Instance{}
+		json.Unmarshal(body, &instance)
+		mutex.Lock()
+		sent[instance.Name] = instance
+		mutex.Unlock()
+		return []byte(`{"id":"id-` + instance.Name + `"}`), nil
+	})
+
+	//when
+	fleet, err := resources.LaunchFleet(FleetRequest{
+		Spec:        Instance{TemplateId: "template", ComputeOfferingId: "offering", NetworkId: "network", SSHKeyName: "key"},
+		Count:       3,
+		NamePattern: "web-%02d",
+		Placements: []FleetPlacement{
+			{ZoneId: "zone1", AffinityGroupIds: []string{"group1"}},
+			{ZoneId: "zone2", NetworkId: "network2"},
+		},
+	})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Empty(t, fleet.Failures)
+		assert.Equal(t, []Instance{
+			{Id: "id-web-01", Name: "web-01"},
+			{Id: "id-web-02", Name: "web-02"},
+			{Id: "id-web-03", Name: "web-03"},
+		}, fleet.Instances)
+	}
+	assert.Equal(t, Instance{Name: "web-01", TemplateId: "template", ComputeOfferingId: "offering", NetworkId: "network", SSHKeyName: "key", ZoneId: "zone1", AffinityGroupIds: []string{"group1"}}, sent["web-01"])
+	assert.Equal(t, Instance{Name: "web-02", TemplateId: "template", ComputeOfferingId: "offering", NetworkId: "network2", SSHKeyName: "key", ZoneId: "zone2"}, sent["web-02"])
+	assert.Equal(t, "zone1", sent["web-03"].ZoneId)
+}
+
+func TestLaunchFleetKeepsPartialFleetAboveMinCount(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	resources := Resources{Instances: &InstanceApi{entityService: mockEntityService}}
+
+	mockError := mocks.MockError{Message: "some_create_error"}
+	mockEntityService.EXPECT().Create(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(body []byte, options map[string]string) ([]byte, error) {
+		instance := Instance{}
+		json.Unmarshal(body, &instance)
+		if instance.Name == "db-6" {
+			return nil, mockError
+		}
+		return []byte(`{"id":"id-` + instance.Name + `"}`), nil
+	})
+
+	//when
+	fleet, err := resources.LaunchFleet(FleetRequest{Count: 2, MinCount: 1, NamePattern: "db-%d", FirstIndex: 5})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Instance{{Id: "id-db-5", Name: "db-5"}}, fleet.Instances)
+		assert.Equal(t, []FleetFailure{{Name: "db-6", Error: mockError}}, fleet.Failures)
+	}
+}
+
+func TestLaunchFleetRollsBackIfMinCountIsNotReached(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	resources := Resources{Instances: &InstanceApi{entityService: mockEntityService}}
+
+	mockError := mocks.MockError{Message: "some_create_error"}
+	mockEntityService.EXPECT().Create(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(func(body []byte, options map[string]string) ([]byte, error) {
+		instance := Instance{}
+		json.Unmarshal(body, &instance)
+		if instance.Name != "web-1" {
+			return nil, mockError
+		}
+		return []byte(`{"id":"id-web-1"}`), nil
+	})
+	expectedDestroyBody, _ := json.Marshal(DestroyOptions{PurgeImmediately: true})
+	mockEntityService.EXPECT().Delete("id-web-1", expectedDestroyBody, gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	fleet, err := resources.LaunchFleet(FleetRequest{Count: 3, MinCount: 2, NamePattern: "web-%d", Concurrency: 1})
+
+	//then
+	assert.Nil(t, fleet)
+	if assert.IsType(t, FleetError{}, err) {
+		fleetError := err.(FleetError)
+		assert.Equal(t, 1, fleetError.Created)
+		assert.Equal(t, 2, fleetError.MinCount)
+		assert.Len(t, fleetError.Failures, 2)
+		assert.Empty(t, fleetError.RollbackFailures)
+	}
+}
+
+func TestLaunchFleetReturnsErrorIfRequestIsInvalid(t *testing.T) {
+	//given
+	resources := Resources{}
+
+	//when
+	_, countErr := resources.LaunchFleet(FleetRequest{Count: 0, NamePattern: "web-%d"})
+	_, minCountErr := resources.LaunchFleet(FleetRequest{Count: 2, MinCount: 3, NamePattern: "web-%d"})
+	_, patternErr := resources.LaunchFleet(FleetRequest{Count: 2, NamePattern: "web"})
+
+	//then
+	assert.Error(t, countErr)
+	assert.Error(t, minCountErr)
+	assert.Error(t, patternErr)
+}
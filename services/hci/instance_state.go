@@ -0,0 +1,111 @@
+package hci
+
+import (
+	"strings"
+)
+
+// Key of the destroy operation in instanceOperationStates.
+// Not an operation of the API: instances are destroyed with a DELETE request, not with Execute.
+const instanceDestroyOperation = "destroy"
+
+// Lifecycle of an instance:
+//
+//	Starting -> Running -> Stopping -> Stopped -> Starting
+//	Running or Stopped -> Migrating -> Running or Stopped
+//	Running, Stopped or Error -> Destroyed -> Expunging (purged, the instance no longer exists)
+//	Destroyed -> Stopped (recovered)
+//
+// Starting, Stopping, Migrating and Expunging are transient: no lifecycle operation is allowed until the instance leaves them.
+var instanceOperationStates = map[string][]string{
+	INSTANCE_START_OPERATION:   {INSTANCE_STATE_STOPPED},
+	INSTANCE_STOP_OPERATION:    {INSTANCE_STATE_RUNNING},
+	INSTANCE_REBOOT_OPERATION:  {INSTANCE_STATE_RUNNING},
+	INSTANCE_RECOVER_OPERATION: {INSTANCE_STATE_DESTROYED},
+	INSTANCE_PURGE_OPERATION:   {INSTANCE_STATE_DESTROYED},
+	instanceDestroyOperation:   {INSTANCE_STATE_RUNNING, INSTANCE_STATE_STOPPED, INSTANCE_STATE_ERROR},
+}
+
+// Returned by a guarded InstanceService when the state of the instance does not allow the operation
+type InvalidInstanceStateError struct {
+	InstanceId    string
+	Operation     string
+	State         string
+	AllowedStates []string
+}
+
+func (e InvalidInstanceStateError) Error() string {
+	return "Cannot " + e.Operation + " instance " + e.InstanceId + " in state " + e.State + ", it must be " + strings.Join(e.AllowedStates, " or ")
+}
+
+// Get the states in which the lifecycle operation is allowed. Returns nil for operations not bound to a state.
+func AllowedInstanceStates(operation string) []string {
+	return instanceOperationStates[operation]
+}
+
+func (instance *Instance) canDo(operation string) bool {
+	for _, state := range instanceOperationStates[operation] {
+		if strings.EqualFold(instance.State, state) {
+			return true
+		}
+	}
+	return false
+}
+
+func (instance *Instance) IsDestroyed() bool {
+	return strings.EqualFold(instance.State, INSTANCE_STATE_DESTROYED)
+}
+
+// Check if the instance is in a transient state, waiting to reach a stable one
+func (instance *Instance) IsTransitioning() bool {
+	for _, state := range []string{INSTANCE_STATE_STARTING, INSTANCE_STATE_STOPPING, INSTANCE_STATE_MIGRATING, INSTANCE_STATE_EXPUNGING} {
+		if strings.EqualFold(instance.State, state) {
+			return true
+		}
+	}
+	return false
+}
+
+func (instance *Instance) CanStart() bool {
+	return instance.canDo(INSTANCE_START_OPERATION)
+}
+
+func (instance *Instance) CanStop() bool {
+	return instance.canDo(INSTANCE_STOP_OPERATION)
+}
+
+func (instance *Instance) CanReboot() bool {
+	return instance.canDo(INSTANCE_REBOOT_OPERATION)
+}
+
+// Only destroyed instances can be recovered. Purged instances are gone for good.
+func (instance *Instance) CanRecover() bool {
+	return instance.canDo(INSTANCE_RECOVER_OPERATION)
+}
+
+func (instance *Instance) CanPurge() bool {
+	return instance.canDo(INSTANCE_PURGE_OPERATION)
+}
+
+func (instance *Instance) CanDestroy() bool {
+	return instance.canDo(instanceDestroyOperation)
+}
+
+// Fetch the instance and check its state allows the operation. Does nothing if the service is not guarded.
+func (instanceApi *InstanceApi) checkState(id string, operation string) error {
+	if !instanceApi.guard {
+		return nil
+	}
+	instance, err := instanceApi.Get(id)
+	if err != nil {
+		return err
+	}
+	if !instance.canDo(operation) {
+		return InvalidInstanceStateError{
+			InstanceId:    id,
+			Operation:     operation,
+			State:         instance.State,
+			AllowedStates: instanceOperationStates[operation],
+		}
+	}
+	return nil
+}
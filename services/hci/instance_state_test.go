@@ -0,0 +1,86 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceLifecycleHelpers(t *testing.T) {
+	running := Instance{State: "Running"}
+	stopped := Instance{State: "stopped"}
+	destroyed := Instance{State: INSTANCE_STATE_DESTROYED}
+	starting := Instance{State: INSTANCE_STATE_STARTING}
+
+	assert.True(t, stopped.CanStart())
+	assert.False(t, running.CanStart())
+	assert.True(t, running.CanStop())
+	assert.True(t, running.CanReboot())
+	assert.False(t, stopped.CanReboot())
+	assert.True(t, destroyed.CanRecover())
+	assert.True(t, destroyed.CanPurge())
+	assert.False(t, running.CanRecover())
+	assert.True(t, running.CanDestroy())
+	assert.False(t, destroyed.CanDestroy())
+	assert.False(t, starting.CanStop())
+	assert.True(t, starting.IsTransitioning())
+	assert.False(t, running.IsTransitioning())
+}
+
+func TestGuardedStartRefusesRunningInstance(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	instanceService := InstanceApi{entityService: mockEntityService, guard: true}
+	mockEntityService.EXPECT().Get(TEST_INSTANCE_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_INSTANCE_ID+`","state":"Running"}`), nil)
+
+	//when
+	success, err := instanceService.Start(TEST_INSTANCE_ID)
+
+	//then
+	assert.False(t, success)
+	assert.Equal(t, InvalidInstanceStateError{
+		InstanceId:    TEST_INSTANCE_ID,
+		Operation:     INSTANCE_START_OPERATION,
+		State:         INSTANCE_STATE_RUNNING,
+		AllowedStates: []string{INSTANCE_STATE_STOPPED},
+	}, err)
+}
+
+func TestGuardedRecoverCallsOperationOnDestroyedInstance(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	instanceService := InstanceApi{entityService: mockEntityService, guard: true}
+	mockEntityService.EXPECT().Get(TEST_INSTANCE_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_INSTANCE_ID+`","state":"Destroyed"}`), nil)
+	mockEntityService.EXPECT().Execute(TEST_INSTANCE_ID, INSTANCE_RECOVER_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	success, err := instanceService.Recover(TEST_INSTANCE_ID)
+
+	//then
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
+
+func TestGuardedDestroyRefusesDestroyedInstance(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	instanceService := InstanceApi{entityService: mockEntityService, guard: true}
+	mockEntityService.EXPECT().Get(TEST_INSTANCE_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_INSTANCE_ID+`","state":"Destroyed"}`), nil)
+
+	//when
+	_, err := instanceService.Destroy(TEST_INSTANCE_ID, true)
+
+	//then
+	assert.IsType(t, InvalidInstanceStateError{}, err)
+}
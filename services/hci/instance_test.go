@@ -227,11 +227,15 @@ func TestCreateInstanceReturnCreatedInstanceIfSuccess(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
 
 	instanceService := InstanceApi{
-		entityService: mockEntityService,
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
 	}
 
+	mockComputeOfferingEntityService.EXPECT().Get("computeOfferingId", gomock.Any()).Return([]byte(`{"id":"computeOfferingId"}`), nil)
+
 	instanceToCreate := Instance{Id: "new_id",
 		Name:              "new_name",
 		TemplateId:        "templateId",
@@ -245,7 +249,7 @@ func TestCreateInstanceReturnCreatedInstanceIfSuccess(t *testing.T) {
 
 	//then
 	if assert.NotNil(t, createdInstance) {
-		assert.Equal(t, "new_password", createdInstance.Password)
+		assert.Equal(t, "new_password", createdInstance.Password.Reveal())
 	}
 }
 
@@ -255,11 +259,15 @@ func TestCreateInstanceReturnNilWithErrorIfError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
 
 	instanceService := InstanceApi{
-		entityService: mockEntityService,
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
 	}
 
+	mockComputeOfferingEntityService.EXPECT().Get("computeOfferingId", gomock.Any()).Return([]byte(`{"id":"computeOfferingId"}`), nil)
+
 	mockError := mocks.MockError{"some_create_instance_error"}
 
 	mockEntityService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, mockError)
@@ -622,11 +630,15 @@ func TestChangeComputeOfferingReturnTrueIfSuccess(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
 
 	instanceService := InstanceApi{
-		entityService: mockEntityService,
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
 	}
 
+	mockComputeOfferingEntityService.EXPECT().Get("new_compute_offering", gomock.Any()).Return([]byte(`{"id":"new_compute_offering"}`), nil)
+
 	instanceWithNewComputeOffering := Instance{
 		Id:                TEST_INSTANCE_ID,
 		ComputeOfferingId: "new_compute_offering",
@@ -647,11 +659,15 @@ func TestChangeComputeOfferingReturnFalseIfError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
 
 	instanceService := InstanceApi{
-		entityService: mockEntityService,
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
 	}
 
+	mockComputeOfferingEntityService.EXPECT().Get("new_compute_offering", gomock.Any()).Return([]byte(`{"id":"new_compute_offering"}`), nil)
+
 	mockError := mocks.MockError{"some_change_compute_offering_error"}
 	mockEntityService.EXPECT().Execute(TEST_INSTANCE_ID, INSTANCE_CHANGE_COMPUTE_OFFERING_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), mockError)
 
@@ -710,6 +726,27 @@ func TestResetPasswordReturnEmptyStringIfError(t *testing.T) {
 	assert.Equal(t, mockError, err)
 }
 
+func TestGetEncryptedPasswordReturnEncryptedPasswordIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	instanceService := InstanceApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().Execute(TEST_INSTANCE_ID, INSTANCE_GET_PASSWORD_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{"encryptedPassword":"ZW5jcnlwdGVk"}`), nil)
+
+	//when
+	encryptedPassword, err := instanceService.GetEncryptedPassword(TEST_INSTANCE_ID)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "ZW5jcnlwdGVk", encryptedPassword)
+}
+
 func TestCreateRecoveryPointReturnTrueIfSuccess(t *testing.T) {
 	//given
 	ctrl := gomock.NewController(t)
@@ -724,7 +761,7 @@ func TestCreateRecoveryPointReturnTrueIfSuccess(t *testing.T) {
 	mockEntityService.EXPECT().Execute(TEST_INSTANCE_ID, INSTANCE_CREATE_RECOVERY_POINT_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
 
 	//when
-	success, _ := instanceService.CreateRecoveryPoint(TEST_INSTANCE_ID, RecoveryPoint{"new_recovery_point_name", "description"})
+	success, _ := instanceService.CreateRecoveryPoint(TEST_INSTANCE_ID, RecoveryPoint{Name: "new_recovery_point_name", Description: "description"})
 
 	//then
 	assert.True(t, success)
@@ -745,7 +782,7 @@ func TestCreateRecoveryPointReturnFalseIfError(t *testing.T) {
 	mockEntityService.EXPECT().Execute(TEST_INSTANCE_ID, INSTANCE_CREATE_RECOVERY_POINT_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), mockError)
 
 	//when
-	success, err := instanceService.CreateRecoveryPoint(TEST_INSTANCE_ID, RecoveryPoint{"new_recovery_point_name", "description"})
+	success, err := instanceService.CreateRecoveryPoint(TEST_INSTANCE_ID, RecoveryPoint{Name: "new_recovery_point_name", Description: "description"})
 
 	//then
 	assert.False(t, success)
@@ -814,3 +851,149 @@ func TestExistsReturnErrorIfUnexpectedError(t *testing.T) {
 	//then
 	assert.Equal(t, mockError, err)
 }
+
+func TestUpdateInstanceSendsOnlyMutableAttributesAndReturnsUpdatedInstance(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	instanceService := InstanceApi{
+		entityService: mockEntityService,
+	}
+
+	instanceToUpdate := Instance{Id: TEST_INSTANCE_ID,
+		Name:              "new_name",
+		Description:       "new_description",
+		ComputeOfferingId: "ignored_compute_offering_id"}
+
+	mockEntityService.EXPECT().Update(TEST_INSTANCE_ID, gomock.Any(), gomock.Any()).DoAndReturn(func(id string, body []byte, options map[string]string) ([]byte, error) {
+		assert.Equal(t, `{"name":"new_name","description":"new_description","recoveryPoint":{}}`, string(body))
+		return []byte(`{"id":"` + TEST_INSTANCE_ID + `", "name":"new_name", "description":"new_description"}`), nil
+	})
+
+	//when
+	updatedInstance, err := instanceService.Update(instanceToUpdate)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, &Instance{Id: TEST_INSTANCE_ID, Name: "new_name", Description: "new_description"}, updatedInstance)
+}
+
+func TestUpdateInstanceReturnNilWithErrorIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	instanceService := InstanceApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_update_instance_error"}
+	mockEntityService.EXPECT().Update(TEST_INSTANCE_ID, gomock.Any(), gomock.Any()).Return(nil, mockError)
+
+	//when
+	updatedInstance, err := instanceService.Update(Instance{Id: TEST_INSTANCE_ID, Name: "new_name"})
+
+	//then
+	assert.Nil(t, updatedInstance)
+	assert.Equal(t, mockError, err)
+}
+
+func TestCreateInstanceReturnErrorIfCustomValuesSetForFixedComputeOffering(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	instanceService := InstanceApi{
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
+	}
+
+	mockComputeOfferingEntityService.EXPECT().Get("fixed_offering", gomock.Any()).Return([]byte(`{"id":"fixed_offering","cpuCount":1,"memoryInMB":1024}`), nil).Times(1)
+
+	//when
+	createdInstance, err := instanceService.Create(Instance{Name: "new_name", ComputeOfferingId: "fixed_offering", CpuCount: 4, MemoryInMB: 4096})
+	_, secondErr := instanceService.Create(Instance{Name: "new_name", ComputeOfferingId: "fixed_offering", MemoryInMB: 4096})
+
+	//then
+	assert.Nil(t, createdInstance)
+	assert.Error(t, err)
+	assert.Error(t, secondErr)
+}
+
+func TestChangeComputeOfferingReturnErrorIfCustomValuesSetForFixedComputeOffering(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	instanceService := InstanceApi{
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
+	}
+
+	mockComputeOfferingEntityService.EXPECT().Get("fixed_offering", gomock.Any()).Return([]byte(`{"id":"fixed_offering","cpuCount":4,"memoryInMB":4096}`), nil)
+
+	//when
+	success, err := instanceService.ChangeComputeOffering(Instance{Id: TEST_INSTANCE_ID, NewComputeOfferingId: "fixed_offering", CpuCount: 2, MemoryInMB: 2048})
+
+	//then
+	assert.False(t, success)
+	assert.Error(t, err)
+}
+
+func TestCreateInstanceReturnErrorIfCustomValuesOutOfRange(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	instanceService := InstanceApi{
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
+	}
+
+	mockComputeOfferingEntityService.EXPECT().Get("custom_offering", gomock.Any()).Return([]byte(`{"id":"custom_offering","custom":true,"maxCpuCount":8}`), nil)
+
+	//when
+	createdInstance, err := instanceService.Create(Instance{Name: "new_name", ComputeOfferingId: "custom_offering", CpuCount: 16, MemoryInMB: 4096})
+
+	//then
+	assert.Nil(t, createdInstance)
+	assert.Error(t, err)
+}
+
+func TestChangeComputeOfferingSendsValidCustomValues(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockComputeOfferingEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	instanceService := InstanceApi{
+		entityService:          mockEntityService,
+		computeOfferingService: &ComputeOfferingApi{entityService: mockComputeOfferingEntityService},
+	}
+
+	mockComputeOfferingEntityService.EXPECT().Get("custom_offering", gomock.Any()).Return([]byte(`{"id":"custom_offering","custom":true}`), nil)
+	mockEntityService.EXPECT().Execute(TEST_INSTANCE_ID, INSTANCE_CHANGE_COMPUTE_OFFERING_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	success, err := instanceService.ChangeComputeOffering(Instance{Id: TEST_INSTANCE_ID, ComputeOfferingId: "custom_offering", CpuCount: 2, MemoryInMB: 2048})
+
+	//then
+	assert.True(t, success)
+	assert.NoError(t, err)
+}
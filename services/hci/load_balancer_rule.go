@@ -27,6 +27,12 @@ type LoadBalancerRule struct {
 	StickinessPolicyParameters map[string]string `json:"stickinessPolicyParameters,omitempty"`
 }
 
+// Instances of a load balancer rule, always sent so the last instance can be removed
+type loadBalancerRuleInstances struct {
+	Id          string   `json:"id,omitempty"`
+	InstanceIds []string `json:"instanceIds"`
+}
+
 type LoadBalancerRuleService interface {
 	Get(id string) (*LoadBalancerRule, error)
 	List() ([]LoadBalancerRule, error)
@@ -105,12 +111,15 @@ func (api *LoadBalancerRuleApi) Update(lbr LoadBalancerRule) (*LoadBalancerRule,
 	return parseLoadBalancerRule(result), nil
 }
 
+// Replace the instances of the load balancer rule with the specified id. An empty list removes all of them.
 func (api *LoadBalancerRuleApi) SetLoadBalancerRuleInstances(id string, instanceIds []string) error {
-	lbr := LoadBalancerRule{
+	if instanceIds == nil {
+		instanceIds = []string{}
+	}
+	msg, err := json.Marshal(loadBalancerRuleInstances{
 		Id:          id,
 		InstanceIds: instanceIds,
-	}
-	msg, err := json.Marshal(lbr)
+	})
 	if err != nil {
 		return err
 	}
@@ -9,6 +9,8 @@ import (
 const (
 	PUBLIC_IP_ENABLE_STATIC_NAT_OPERATION  = "enableStaticNat"
 	PUBLIC_IP_DISABLE_STATIC_NAT_OPERATION = "disableStaticNat"
+
+	PUBLIC_IP_PURPOSE_STATIC_NAT = "STATIC_NAT"
 )
 
 type PublicIp struct {
@@ -1,6 +1,124 @@
 package hci
 
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/services"
+)
+
+const (
+	RECOVERY_POINT_REVERT_OPERATION = "revert"
+)
+
+const (
+	RECOVERY_POINT_STATE_READY    = "Ready"
+	RECOVERY_POINT_STATE_CREATING = "Creating"
+	RECOVERY_POINT_STATE_ERROR    = "Error"
+)
+
 type RecoveryPoint struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
+	Id           string `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	InstanceId   string `json:"instanceId,omitempty"`
+	InstanceName string `json:"instanceName,omitempty"`
+	State        string `json:"state,omitempty"`
+	Created      string `json:"created,omitempty"`
+	Current      bool   `json:"current,omitempty"`
+}
+
+// Formats accepted for the creation date of a recovery point
+var recoveryPointTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05",
+}
+
+// Parse the creation date of the recovery point
+func (recoveryPoint *RecoveryPoint) CreationTime() (time.Time, error) {
+	var err error
+	for _, layout := range recoveryPointTimeLayouts {
+		var created time.Time
+		created, err = time.Parse(layout, recoveryPoint.Created)
+		if err == nil {
+			return created, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+type RecoveryPointService interface {
+	Get(id string) (*RecoveryPoint, error)
+	List() ([]RecoveryPoint, error)
+	ListOfInstance(instanceId string) ([]RecoveryPoint, error)
+	ListWithOptions(options map[string]string) ([]RecoveryPoint, error)
+	Revert(id string) (bool, error)
+	Delete(id string) (bool, error)
+}
+
+type RecoveryPointApi struct {
+	entityService services.EntityService
+}
+
+func NewRecoveryPointService(apiClient api.ApiClient, serviceCode string, environmentName string) RecoveryPointService {
+	return &RecoveryPointApi{
+		entityService: services.NewEntityService(apiClient, serviceCode, environmentName, RECOVERY_POINT_ENTITY_TYPE),
+	}
+}
+
+func parseRecoveryPoint(data []byte) *RecoveryPoint {
+	recoveryPoint := RecoveryPoint{}
+	json.Unmarshal(data, &recoveryPoint)
+	return &recoveryPoint
+}
+
+func parseRecoveryPointList(data []byte) []RecoveryPoint {
+	recoveryPoints := []RecoveryPoint{}
+	json.Unmarshal(data, &recoveryPoints)
+	return recoveryPoints
+}
+
+// Get recovery point with the specified id for the current environment
+func (recoveryPointApi *RecoveryPointApi) Get(id string) (*RecoveryPoint, error) {
+	data, err := recoveryPointApi.entityService.Get(id, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseRecoveryPoint(data), nil
+}
+
+// List all recovery points for the current environment
+func (recoveryPointApi *RecoveryPointApi) List() ([]RecoveryPoint, error) {
+	return recoveryPointApi.ListWithOptions(map[string]string{})
+}
+
+// List all recovery points of an instance for the current environment
+func (recoveryPointApi *RecoveryPointApi) ListOfInstance(instanceId string) ([]RecoveryPoint, error) {
+	return recoveryPointApi.ListWithOptions(map[string]string{
+		"instanceId": instanceId,
+	})
+}
+
+// List all recovery points for the current environment. Can use options to do sorting and paging.
+func (recoveryPointApi *RecoveryPointApi) ListWithOptions(options map[string]string) ([]RecoveryPoint, error) {
+	data, err := recoveryPointApi.entityService.List(options)
+	if err != nil {
+		return nil, err
+	}
+	return parseRecoveryPointList(data), nil
+}
+
+// Revert the instance of the recovery point with the specified id to the state it was in when the recovery point was created
+// Note: Any change made to the instance after the recovery point was created is lost
+func (recoveryPointApi *RecoveryPointApi) Revert(id string) (bool, error) {
+	_, err := recoveryPointApi.entityService.Execute(id, RECOVERY_POINT_REVERT_OPERATION, []byte{}, map[string]string{})
+	return err == nil, err
+}
+
+// Delete the recovery point with the specified id in the current environment
+func (recoveryPointApi *RecoveryPointApi) Delete(id string) (bool, error) {
+	_, err := recoveryPointApi.entityService.Delete(id, []byte{}, map[string]string{})
+	return err == nil, err
 }
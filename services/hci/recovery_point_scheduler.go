@@ -0,0 +1,276 @@
+package hci
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Actions reported in a RecoveryPointEvent
+const (
+	RECOVERY_POINT_EVENT_CREATED = "created"
+	RECOVERY_POINT_EVENT_PRUNED  = "pruned"
+	RECOVERY_POINT_EVENT_LISTED  = "listed"
+)
+
+// Default delay between two evaluations of the policies by a running scheduler
+const DEFAULT_RECOVERY_POINT_SCHEDULER_TICK = time.Minute
+
+// Layout of the timestamp appended to the name of the recovery points created by a policy
+const RECOVERY_POINT_POLICY_TIME_LAYOUT = "20060102-150405"
+
+// Creates recovery points of the matching instances every Interval and keeps only the Retain most recent ones.
+// Recovery points created by a policy are named "<Name>-<timestamp>", which is how the policy recognizes
+// the recovery points it owns when pruning. Recovery points created by other means are never deleted.
+type RecoveryPointPolicy struct {
+	Name string
+	// Ids of the instances covered by the policy
+	InstanceIds []string
+	// Regular expression matched against the names of the instances covered by the policy
+	NamePattern string
+	Interval    time.Duration
+	// Number of recovery points to keep per instance. 0 keeps all of them.
+	Retain int
+}
+
+// Reported to the OnEvent callback for every recovery point created or pruned, successfully or not
+type RecoveryPointEvent struct {
+	Time          time.Time
+	Policy        string
+	Action        string
+	InstanceId    string
+	InstanceName  string
+	RecoveryPoint RecoveryPoint
+	Error         error
+}
+
+type RecoveryPointSchedulerOptions struct {
+	// Defaults to the system clock
+	Clock Clock
+	// Delay between two evaluations of the policies. Defaults to DEFAULT_RECOVERY_POINT_SCHEDULER_TICK.
+	TickInterval time.Duration
+	OnEvent      func(event RecoveryPointEvent)
+}
+
+// Applies recovery point policies to the instances of an environment
+type RecoveryPointScheduler struct {
+	instances      InstanceService
+	recoveryPoints RecoveryPointService
+	policies       []RecoveryPointPolicy
+	patterns       []*regexp.Regexp
+	clock          Clock
+	tickInterval   time.Duration
+	onEvent        func(event RecoveryPointEvent)
+	lastRuns       map[string]time.Time
+	mutex          sync.Mutex
+	// Guards stop and done, which are nil while the scheduler is not started
+	lifecycleMutex sync.Mutex
+	stop           chan struct{}
+	done           chan struct{}
+}
+
+// Create a scheduler applying the policies to the instances of the Resources environment. Returns an error if a policy is invalid.
+func NewRecoveryPointScheduler(resources Resources, policies []RecoveryPointPolicy, options RecoveryPointSchedulerOptions) (*RecoveryPointScheduler, error) {
+	patterns := make([]*regexp.Regexp, len(policies))
+	for i, policy := range policies {
+		if policy.Name == "" {
+			return nil, fmt.Errorf("Recovery point policy %d has no name", i)
+		}
+		if policy.Interval <= 0 {
+			return nil, fmt.Errorf("Recovery point policy %s must have a positive interval", policy.Name)
+		}
+		if policy.Retain < 0 {
+			return nil, fmt.Errorf("Recovery point policy %s cannot retain a negative number of recovery points", policy.Name)
+		}
+		if len(policy.InstanceIds) == 0 && policy.NamePattern == "" {
+			return nil, fmt.Errorf("Recovery point policy %s must have instance ids or a name pattern", policy.Name)
+		}
+		if policy.NamePattern != "" {
+			pattern, err := regexp.Compile(policy.NamePattern)
+			if err != nil {
+				return nil, fmt.Errorf("Recovery point policy %s has an invalid name pattern: %s", policy.Name, err)
+			}
+			patterns[i] = pattern
+		}
+	}
+	scheduler := &RecoveryPointScheduler{
+		instances:      resources.Instances,
+		recoveryPoints: resources.RecoveryPoints,
+		policies:       policies,
+		patterns:       patterns,
+		clock:          options.Clock,
+		tickInterval:   options.TickInterval,
+		onEvent:        options.OnEvent,
+		lastRuns:       map[string]time.Time{},
+	}
+	if scheduler.clock == nil {
+		scheduler.clock = systemClock{}
+	}
+	if scheduler.tickInterval <= 0 {
+		scheduler.tickInterval = DEFAULT_RECOVERY_POINT_SCHEDULER_TICK
+	}
+	if scheduler.onEvent == nil {
+		scheduler.onEvent = func(RecoveryPointEvent) {}
+	}
+	return scheduler, nil
+}
+
+// Evaluate the policies every tick in a new goroutine, until Stop is called. Does nothing if the scheduler is already started.
+func (scheduler *RecoveryPointScheduler) Start() {
+	scheduler.lifecycleMutex.Lock()
+	defer scheduler.lifecycleMutex.Unlock()
+	if scheduler.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	scheduler.stop = stop
+	scheduler.done = done
+	go func() {
+		defer close(done)
+		for {
+			scheduler.RunOnce()
+			select {
+			case <-stop:
+				return
+			case <-scheduler.clock.After(scheduler.tickInterval):
+			}
+		}
+	}()
+}
+
+// Stop a started scheduler. Blocks until the current evaluation, if any, is done.
+// Does nothing if the scheduler is not started. A stopped scheduler can be started again.
+func (scheduler *RecoveryPointScheduler) Stop() {
+	scheduler.lifecycleMutex.Lock()
+	defer scheduler.lifecycleMutex.Unlock()
+	if scheduler.stop == nil {
+		return
+	}
+	close(scheduler.stop)
+	<-scheduler.done
+	scheduler.stop = nil
+	scheduler.done = nil
+}
+
+// Evaluate all the policies once: create the recovery points that are due and prune the old ones
+func (scheduler *RecoveryPointScheduler) RunOnce() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	now := scheduler.clock.Now()
+	instances, err := scheduler.instances.List()
+	if err != nil {
+		scheduler.onEvent(RecoveryPointEvent{Time: now, Action: RECOVERY_POINT_EVENT_LISTED, Error: err})
+		return
+	}
+	for i, policy := range scheduler.policies {
+		for _, instance := range instances {
+			if scheduler.covers(i, instance) {
+				scheduler.apply(policy, instance, now)
+			}
+		}
+	}
+}
+
+func (scheduler *RecoveryPointScheduler) covers(policyIndex int, instance Instance) bool {
+	if !instance.IsRunning() && !instance.IsStopped() {
+		return false
+	}
+	for _, id := range scheduler.policies[policyIndex].InstanceIds {
+		if id == instance.Id {
+			return true
+		}
+	}
+	pattern := scheduler.patterns[policyIndex]
+	return pattern != nil && pattern.MatchString(instance.Name)
+}
+
+func (scheduler *RecoveryPointScheduler) apply(policy RecoveryPointPolicy, instance Instance, now time.Time) {
+	key := policy.Name + "/" + instance.Id
+	lastRun, ok := scheduler.lastRuns[key]
+	if !ok {
+		owned, err := scheduler.listOwned(policy, instance.Id)
+		if err != nil {
+			scheduler.report(policy, instance, RECOVERY_POINT_EVENT_LISTED, RecoveryPoint{}, now, err)
+			return
+		}
+		if len(owned) > 0 {
+			lastRun, _ = policyRecoveryPointTime(policy, owned[0])
+			scheduler.lastRuns[key] = lastRun
+		}
+	}
+	if !lastRun.IsZero() && now.Sub(lastRun) < policy.Interval {
+		return
+	}
+
+	recoveryPoint := RecoveryPoint{
+		Name:        policy.Name + "-" + now.UTC().Format(RECOVERY_POINT_POLICY_TIME_LAYOUT),
+		Description: "Created by recovery point policy " + policy.Name,
+	}
+	_, err := scheduler.instances.CreateRecoveryPoint(instance.Id, recoveryPoint)
+	scheduler.report(policy, instance, RECOVERY_POINT_EVENT_CREATED, recoveryPoint, now, err)
+	if err != nil {
+		return
+	}
+	scheduler.lastRuns[key] = now
+	scheduler.prune(policy, instance, now)
+}
+
+// Delete the recovery points of the policy beyond the number to retain
+func (scheduler *RecoveryPointScheduler) prune(policy RecoveryPointPolicy, instance Instance, now time.Time) {
+	if policy.Retain == 0 {
+		return
+	}
+	owned, err := scheduler.listOwned(policy, instance.Id)
+	if err != nil {
+		scheduler.report(policy, instance, RECOVERY_POINT_EVENT_LISTED, RecoveryPoint{}, now, err)
+		return
+	}
+	for i := policy.Retain; i < len(owned); i++ {
+		_, err := scheduler.recoveryPoints.Delete(owned[i].Id)
+		scheduler.report(policy, instance, RECOVERY_POINT_EVENT_PRUNED, owned[i], now, err)
+	}
+}
+
+// List the recovery points created by the policy for the instance, most recent first
+func (scheduler *RecoveryPointScheduler) listOwned(policy RecoveryPointPolicy, instanceId string) ([]RecoveryPoint, error) {
+	recoveryPoints, err := scheduler.recoveryPoints.ListOfInstance(instanceId)
+	if err != nil {
+		return nil, err
+	}
+	owned := []RecoveryPoint{}
+	for _, recoveryPoint := range recoveryPoints {
+		if _, ok := policyRecoveryPointTime(policy, recoveryPoint); ok {
+			owned = append(owned, recoveryPoint)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].Name > owned[j].Name
+	})
+	return owned, nil
+}
+
+func (scheduler *RecoveryPointScheduler) report(policy RecoveryPointPolicy, instance Instance, action string, recoveryPoint RecoveryPoint, now time.Time, err error) {
+	scheduler.onEvent(RecoveryPointEvent{
+		Time:          now,
+		Policy:        policy.Name,
+		Action:        action,
+		InstanceId:    instance.Id,
+		InstanceName:  instance.Name,
+		RecoveryPoint: recoveryPoint,
+		Error:         err,
+	})
+}
+
+// Returns the time encoded in the name of a recovery point created by the policy, and false if the policy did not create it
+func policyRecoveryPointTime(policy RecoveryPointPolicy, recoveryPoint RecoveryPoint) (time.Time, bool) {
+	prefix := policy.Name + "-"
+	if !strings.HasPrefix(recoveryPoint.Name, prefix) {
+		return time.Time{}, false
+	}
+	created, err := time.Parse(RECOVERY_POINT_POLICY_TIME_LAYOUT, strings.TrimPrefix(recoveryPoint.Name, prefix))
+	return created, err == nil
+}
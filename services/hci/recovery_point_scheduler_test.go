@@ -0,0 +1,208 @@
+package hci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *fakeClock) After(d time.Duration) <-chan time.Time {
+	return make(chan time.Time)
+}
+
+var TEST_SCHEDULER_NOW = time.Date(2019, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func buildTestScheduler(t *testing.T, ctrl *gomock.Controller, policy RecoveryPointPolicy, events *[]RecoveryPointEvent) (*RecoveryPointScheduler, *services_mocks.MockEntityService, *services_mocks.MockEntityService) {
+	mockInstanceEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockRecoveryPointEntityService := services_mocks.NewMockEntityService(ctrl)
+	resources := Resources{
+		Instances:      &InstanceApi{entityService: mockInstanceEntityService},
+		RecoveryPoints: &RecoveryPointApi{entityService: mockRecoveryPointEntityService},
+	}
+	scheduler, err := NewRecoveryPointScheduler(resources, []RecoveryPointPolicy{policy}, RecoveryPointSchedulerOptions{
+		Clock: &fakeClock{now: TEST_SCHEDULER_NOW},
+		OnEvent: func(event RecoveryPointEvent) {
+			*events = append(*events, event)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scheduler, mockInstanceEntityService, mockRecoveryPointEntityService
+}
+
+func TestRecoveryPointSchedulerCreatesRecoveryPointForMatchingInstances(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []RecoveryPointEvent{}
+	scheduler, mockInstances, mockRecoveryPoints := buildTestScheduler(t, ctrl, RecoveryPointPolicy{
+		Name:        "every6h",
+		NamePattern: "^web-",
+		Interval:    6 * time.Hour,
+		Retain:      8,
+	}, &events)
+
+	mockInstances.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"i1","name":"web-01","state":"Running"},{"id":"i2","name":"db-01","state":"Running"}]`), nil)
+	mockRecoveryPoints.EXPECT().List(map[string]string{"instanceId": "i1"}).Return([]byte(`[{"id":"manual","name":"manual"}]`), nil).Times(2)
+	mockInstances.EXPECT().Execute("i1", INSTANCE_CREATE_RECOVERY_POINT_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	scheduler.RunOnce()
+
+	//then
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, RECOVERY_POINT_EVENT_CREATED, events[0].Action)
+		assert.Equal(t, "i1", events[0].InstanceId)
+		assert.Equal(t, "every6h-20190304-120000", events[0].RecoveryPoint.Name)
+		assert.NoError(t, events[0].Error)
+	}
+}
+
+func TestRecoveryPointSchedulerSkipsInstanceWithRecentRecoveryPoint(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []RecoveryPointEvent{}
+	scheduler, mockInstances, mockRecoveryPoints := buildTestScheduler(t, ctrl, RecoveryPointPolicy{
+		Name:        "every6h",
+		InstanceIds: []string{"i1"},
+		Interval:    6 * time.Hour,
+	}, &events)
+
+	mockInstances.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"i1","name":"web-01","state":"Stopped"}]`), nil).Times(2)
+	mockRecoveryPoints.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"rp1","name":"every6h-20190304-090000"}]`), nil)
+
+	//when
+	scheduler.RunOnce()
+	scheduler.RunOnce()
+
+	//then
+	assert.Empty(t, events)
+}
+
+func TestRecoveryPointSchedulerPrunesOwnedRecoveryPointsBeyondRetain(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []RecoveryPointEvent{}
+	scheduler, mockInstances, mockRecoveryPoints := buildTestScheduler(t, ctrl, RecoveryPointPolicy{
+		Name:        "daily",
+		InstanceIds: []string{"i1"},
+		Interval:    24 * time.Hour,
+		Retain:      2,
+	}, &events)
+
+	mockInstances.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"i1","name":"web-01","state":"Running"}]`), nil)
+	mockRecoveryPoints.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"rp1","name":"daily-20190301-120000"},{"id":"rp2","name":"daily-20190302-120000"}]`), nil)
+	mockInstances.EXPECT().Execute("i1", INSTANCE_CREATE_RECOVERY_POINT_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+	mockRecoveryPoints.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"rp1","name":"daily-20190301-120000"},{"id":"manual","name":"manual"},{"id":"rp3","name":"daily-20190304-120000"},{"id":"rp2","name":"daily-20190302-120000"}]`), nil)
+	mockRecoveryPoints.EXPECT().Delete("rp1", gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	scheduler.RunOnce()
+
+	//then
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, RECOVERY_POINT_EVENT_CREATED, events[0].Action)
+		assert.Equal(t, RECOVERY_POINT_EVENT_PRUNED, events[1].Action)
+		assert.Equal(t, "rp1", events[1].RecoveryPoint.Id)
+	}
+}
+
+func TestRecoveryPointSchedulerReportsFailures(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []RecoveryPointEvent{}
+	scheduler, mockInstances, mockRecoveryPoints := buildTestScheduler(t, ctrl, RecoveryPointPolicy{
+		Name:        "hourly",
+		InstanceIds: []string{"i1"},
+		Interval:    time.Hour,
+		Retain:      1,
+	}, &events)
+
+	mockError := mocks.MockError{Message: "some_create_recovery_point_error"}
+	mockInstances.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"i1","name":"web-01","state":"Running"}]`), nil)
+	mockRecoveryPoints.EXPECT().List(gomock.Any()).Return([]byte(`[]`), nil)
+	mockInstances.EXPECT().Execute("i1", INSTANCE_CREATE_RECOVERY_POINT_OPERATION, gomock.Any(), gomock.Any()).Return(nil, mockError)
+
+	//when
+	scheduler.RunOnce()
+
+	//then
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, mockError, events[0].Error)
+	}
+}
+
+func TestRecoveryPointSchedulerStartAndStop(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []RecoveryPointEvent{}
+	scheduler, mockInstances, _ := buildTestScheduler(t, ctrl, RecoveryPointPolicy{
+		Name:        "hourly",
+		InstanceIds: []string{"i1"},
+		Interval:    time.Hour,
+	}, &events)
+
+	mockInstances.EXPECT().List(gomock.Any()).Return([]byte(`[]`), nil)
+
+	//when
+	scheduler.Start()
+	scheduler.Stop()
+
+	//then
+	assert.Empty(t, events)
+}
+
+func TestRecoveryPointSchedulerIgnoresRepeatedStartAndStop(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := []RecoveryPointEvent{}
+	scheduler, mockInstances, _ := buildTestScheduler(t, ctrl, RecoveryPointPolicy{
+		Name:        "hourly",
+		InstanceIds: []string{"i1"},
+		Interval:    time.Hour,
+	}, &events)
+
+	mockInstances.EXPECT().List(gomock.Any()).Return([]byte(`[]`), nil).Times(1)
+
+	//when
+	scheduler.Stop()
+	scheduler.Start()
+	scheduler.Start()
+	scheduler.Stop()
+	scheduler.Stop()
+
+	//then
+	assert.Empty(t, events)
+}
+
+func TestNewRecoveryPointSchedulerReturnsErrorIfPolicyIsInvalid(t *testing.T) {
+	//when
+	_, err := NewRecoveryPointScheduler(Resources{}, []RecoveryPointPolicy{{Name: "no-target", Interval: time.Hour}}, RecoveryPointSchedulerOptions{})
+
+	//then
+	assert.Error(t, err)
+}
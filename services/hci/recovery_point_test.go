@@ -0,0 +1,193 @@
+package hci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	TEST_RECOVERY_POINT_ID          = "test_recovery_point_id"
+	TEST_RECOVERY_POINT_NAME        = "test_recovery_point"
+	TEST_RECOVERY_POINT_DESCRIPTION = "test_recovery_point_description"
+	TEST_RECOVERY_POINT_STATE       = "Ready"
+	TEST_RECOVERY_POINT_CREATED     = "2019-03-04T15:04:05Z"
+)
+
+func buildRecoveryPointJsonResponse(recoveryPoint *RecoveryPoint) []byte {
+	return []byte(`{"id":"` + recoveryPoint.Id + `",` +
+		`"name":"` + recoveryPoint.Name + `",` +
+		`"description":"` + recoveryPoint.Description + `",` +
+		`"instanceId":"` + recoveryPoint.InstanceId + `",` +
+		`"state":"` + recoveryPoint.State + `",` +
+		`"created":"` + recoveryPoint.Created + `"}`)
+}
+
+func buildListRecoveryPointJsonResponse(recoveryPoints []RecoveryPoint) []byte {
+	resp := `[`
+	for i, r := range recoveryPoints {
+		resp += string(buildRecoveryPointJsonResponse(&r))
+		if i != len(recoveryPoints)-1 {
+			resp += `,`
+		}
+	}
+	resp += `]`
+	return []byte(resp)
+}
+
+func TestGetRecoveryPointReturnRecoveryPointIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	recoveryPointService := RecoveryPointApi{
+		entityService: mockEntityService,
+	}
+
+	expectedRecoveryPoint := RecoveryPoint{
+		Id:          TEST_RECOVERY_POINT_ID,
+		Name:        TEST_RECOVERY_POINT_NAME,
+		Description: TEST_RECOVERY_POINT_DESCRIPTION,
+		InstanceId:  TEST_INSTANCE_ID,
+		State:       TEST_RECOVERY_POINT_STATE,
+		Created:     TEST_RECOVERY_POINT_CREATED,
+	}
+
+	mockEntityService.EXPECT().Get(TEST_RECOVERY_POINT_ID, gomock.Any()).Return(buildRecoveryPointJsonResponse(&expectedRecoveryPoint), nil)
+
+	//when
+	recoveryPoint, _ := recoveryPointService.Get(TEST_RECOVERY_POINT_ID)
+
+	//then
+	if assert.NotNil(t, recoveryPoint) {
+		assert.Equal(t, expectedRecoveryPoint, *recoveryPoint)
+	}
+}
+
+func TestGetRecoveryPointReturnNilWithErrorIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	recoveryPointService := RecoveryPointApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_get_error"}
+
+	mockEntityService.EXPECT().Get(TEST_RECOVERY_POINT_ID, gomock.Any()).Return(nil, mockError)
+
+	//when
+	recoveryPoint, err := recoveryPointService.Get(TEST_RECOVERY_POINT_ID)
+
+	//then
+	assert.Nil(t, recoveryPoint)
+	assert.Equal(t, mockError, err)
+}
+
+func TestListRecoveryPointsOfInstanceFiltersOnInstanceId(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	recoveryPointService := RecoveryPointApi{
+		entityService: mockEntityService,
+	}
+
+	expectedRecoveryPoints := []RecoveryPoint{
+		{Id: "list_id_1", Name: "list_name_1", InstanceId: TEST_INSTANCE_ID, State: "Ready", Created: TEST_RECOVERY_POINT_CREATED},
+		{Id: "list_id_2", Name: "list_name_2", InstanceId: TEST_INSTANCE_ID, State: "Creating", Created: TEST_RECOVERY_POINT_CREATED},
+	}
+
+	mockEntityService.EXPECT().List(map[string]string{"instanceId": TEST_INSTANCE_ID}).Return(buildListRecoveryPointJsonResponse(expectedRecoveryPoints), nil)
+
+	//when
+	recoveryPoints, _ := recoveryPointService.ListOfInstance(TEST_INSTANCE_ID)
+
+	//then
+	assert.Equal(t, expectedRecoveryPoints, recoveryPoints)
+}
+
+func TestRevertRecoveryPointReturnTrueIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	recoveryPointService := RecoveryPointApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().Execute(TEST_RECOVERY_POINT_ID, RECOVERY_POINT_REVERT_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	success, _ := recoveryPointService.Revert(TEST_RECOVERY_POINT_ID)
+
+	//then
+	assert.True(t, success)
+}
+
+func TestRevertRecoveryPointReturnFalseIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	recoveryPointService := RecoveryPointApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_revert_error"}
+	mockEntityService.EXPECT().Execute(TEST_RECOVERY_POINT_ID, RECOVERY_POINT_REVERT_OPERATION, gomock.Any(), gomock.Any()).Return(nil, mockError)
+
+	//when
+	success, err := recoveryPointService.Revert(TEST_RECOVERY_POINT_ID)
+
+	//then
+	assert.False(t, success)
+	assert.Equal(t, mockError, err)
+}
+
+func TestDeleteRecoveryPointReturnTrueIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	recoveryPointService := RecoveryPointApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().Delete(TEST_RECOVERY_POINT_ID, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	success, _ := recoveryPointService.Delete(TEST_RECOVERY_POINT_ID)
+
+	//then
+	assert.True(t, success)
+}
+
+func TestRecoveryPointCreationTimeParsesCreatedDate(t *testing.T) {
+	//given
+	recoveryPoint := RecoveryPoint{Created: TEST_RECOVERY_POINT_CREATED}
+
+	//when
+	created, err := recoveryPoint.CreationTime()
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, 3, 4, 15, 4, 5, 0, time.UTC), created)
+}
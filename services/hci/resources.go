@@ -32,6 +32,9 @@ type Resources struct {
 	LoadBalancerRules   LoadBalancerRuleService
 	RemoteAccessVpn     RemoteAccessVpnService
 	RemoteAccessVpnUser RemoteAccessVpnUserService
+	RecoveryPoints      RecoveryPointService
+	Snapshots           SnapshotService
+	SnapshotPolicies    SnapshotPolicyService
 }
 
 func NewResources(apiClient api.ApiClient, serviceCode string, environmentName string) Resources {
@@ -59,9 +62,22 @@ func NewResources(apiClient api.ApiClient, serviceCode string, environmentName s
 		SSHKeys:             NewSSHKeyService(apiClient, serviceCode, environmentName),
 		RemoteAccessVpn:     NewRemoteAccessVpnService(apiClient, serviceCode, environmentName),
 		RemoteAccessVpnUser: NewRemoteAccessVpnUserService(apiClient, serviceCode, environmentName),
+		RecoveryPoints:      NewRecoveryPointService(apiClient, serviceCode, environmentName),
+		Snapshots:           NewSnapshotService(apiClient, serviceCode, environmentName),
+		SnapshotPolicies:    NewSnapshotPolicyService(apiClient, serviceCode, environmentName),
 	}
 }
 
 func (resources Resources) GetServiceType() string {
 	return HCI_SERVICE
 }
+
+// Get the service code the resources are bound to
+func (resources Resources) GetServiceCode() string {
+	return resources.serviceCode
+}
+
+// Get the name of the environment the resources are bound to
+func (resources Resources) GetEnvironmentName() string {
+	return resources.environmentName
+}
@@ -0,0 +1,42 @@
+package hci
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+)
+
+type resourcesTestMocks struct {
+	instances      *services_mocks.MockEntityService
+	volumes        *services_mocks.MockEntityService
+	diskOfferings  *services_mocks.MockEntityService
+	templates      *services_mocks.MockEntityService
+	publicIps      *services_mocks.MockEntityService
+	pfrs           *services_mocks.MockEntityService
+	lbrs           *services_mocks.MockEntityService
+	recoveryPoints *services_mocks.MockEntityService
+}
+
+// Build resources whose services are backed by mocks, for tests of operations spanning several services
+func buildTestResources(ctrl *gomock.Controller) (Resources, resourcesTestMocks) {
+	mockServices := resourcesTestMocks{
+		instances:      services_mocks.NewMockEntityService(ctrl),
+		volumes:        services_mocks.NewMockEntityService(ctrl),
+		diskOfferings:  services_mocks.NewMockEntityService(ctrl),
+		templates:      services_mocks.NewMockEntityService(ctrl),
+		publicIps:      services_mocks.NewMockEntityService(ctrl),
+		pfrs:           services_mocks.NewMockEntityService(ctrl),
+		lbrs:           services_mocks.NewMockEntityService(ctrl),
+		recoveryPoints: services_mocks.NewMockEntityService(ctrl),
+	}
+	resources := Resources{
+		Instances:           &InstanceApi{entityService: mockServices.instances},
+		Volumes:             &VolumeApi{entityService: mockServices.volumes},
+		DiskOfferings:       &DiskOfferingApi{entityService: mockServices.diskOfferings},
+		Templates:           &TemplateApi{entityService: mockServices.templates},
+		PublicIps:           &PublicIpApi{entityService: mockServices.publicIps},
+		PortForwardingRules: &PortForwardingRuleApi{entityService: mockServices.pfrs},
+		LoadBalancerRules:   &LoadBalancerRuleApi{entityService: mockServices.lbrs},
+		RecoveryPoints:      &RecoveryPointApi{entityService: mockServices.recoveryPoints},
+	}
+	return resources, mockServices
+}
@@ -0,0 +1,89 @@
+package hci
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// Printed in place of the value of a non-empty SecretString
+const SECRET_STRING_REDACTED = "********"
+
+// A string that redacts itself when formatted or marshalled, so it does not leak into logs.
+// Use Reveal to get the actual value, e.g. to send it to the API.
+type SecretString string
+
+// Get the actual value of the secret
+func (secret SecretString) Reveal() string {
+	return string(secret)
+}
+
+func (secret SecretString) redacted() string {
+	if secret == "" {
+		return ""
+	}
+	return SECRET_STRING_REDACTED
+}
+
+func (secret SecretString) String() string {
+	return secret.redacted()
+}
+
+func (secret SecretString) GoString() string {
+	return fmt.Sprintf("%q", secret.redacted())
+}
+
+// Redact the secret whatever the verb (%s, %v, %q, %x...)
+func (secret SecretString) Format(state fmt.State, verb rune) {
+	state.Write([]byte(secret.redacted()))
+}
+
+func (secret SecretString) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + secret.redacted() + `"`), nil
+}
+
+// Decrypt the encrypted password of an instance with the private key of the SSH key associated to the instance.
+// The encrypted password is base64 encoded and the private key must be an unencrypted RSA key in PEM format (PKCS #1 or PKCS #8).
+func DecryptInstancePassword(encryptedPassword string, privateKeyPEM []byte) (SecretString, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encryptedPassword))
+	if err != nil {
+		return "", fmt.Errorf("Encrypted password is not base64 encoded: %s", err)
+	}
+	privateKey, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return "", err
+	}
+	password, err := rsa.DecryptPKCS1v15(rand.Reader, privateKey, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("Failed to decrypt password, check the private key matches the SSH key of the instance: %s", err)
+	}
+	return SecretString(password), nil
+}
+
+func parseRSAPrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("Private key is not in PEM format")
+	}
+	if x509.IsEncryptedPEMBlock(block) {
+		return nil, fmt.Errorf("Private key is protected by a passphrase, decrypt it first")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, fmt.Errorf("Private key is not an RSA key")
+	}
+	return nil, fmt.Errorf("Unsupported private key type %s", block.Type)
+}
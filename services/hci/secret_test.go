@@ -0,0 +1,138 @@
+package hci
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/hypertec-cloud/go-hci/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretStringIsRedactedWhenFormatted(t *testing.T) {
+	//given
+	instance := Instance{Id: "i1", Password: "s3cr3t"}
+
+	//then
+	assert.Equal(t, SECRET_STRING_REDACTED, instance.Password.String())
+	assert.Equal(t, SECRET_STRING_REDACTED, fmt.Sprintf("%s", instance.Password))
+	assert.NotContains(t, fmt.Sprintf("%v %+v %#v %q %x", instance, instance, instance, instance.Password, instance.Password), "s3cr3t")
+	assert.Equal(t, "s3cr3t", instance.Password.Reveal())
+}
+
+func TestSecretStringIsRedactedWhenMarshalled(t *testing.T) {
+	//when
+	data, err := json.Marshal(Instance{Id: "i1", Password: "s3cr3t"})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"i1","password":"********","recoveryPoint":{}}`, string(data))
+}
+
+func TestCreateInstanceSendsActualPassword(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	instanceService := InstanceApi{entityService: mockEntityService}
+
+	mockEntityService.EXPECT().Create([]byte(`{"name":"web","recoveryPoint":{},"password":"s3cr3t"}`), gomock.Any()).Return([]byte(`{"id":"i1"}`), nil)
+
+	//when
+	_, err := instanceService.Create(Instance{Name: "web", Password: "s3cr3t"})
+
+	//then
+	assert.NoError(t, err)
+}
+
+func TestCreateBaremetalSendsActualPassword(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	baremetalService := BaremetalApi{entityService: mockEntityService}
+
+	mockEntityService.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(body []byte, options map[string]string) ([]byte, error) {
+		assert.Contains(t, string(body), `"password":"s3cr3t"`)
+		return []byte(`{"id":"bm1"}`), nil
+	})
+
+	//when
+	_, err := baremetalService.Create(Baremetal{Name: "bm", Password: "s3cr3t"})
+
+	//then
+	assert.NoError(t, err)
+}
+
+func TestCreateAndTrackBaremetalSendsActualPassword(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	mockAsyncEntityService := services_mocks.NewMockAsyncEntityService(ctrl)
+	baremetalService := BaremetalApi{entityService: asyncEntityServiceMock{mockEntityService, mockAsyncEntityService}}
+
+	mockAsyncEntityService.EXPECT().CreateAsync(gomock.Any(), gomock.Any()).DoAndReturn(func(body []byte, options map[string]string) (*services.Task, error) {
+		assert.Contains(t, string(body), `"password":"s3cr3t"`)
+		assert.NotContains(t, string(body), SECRET_STRING_REDACTED)
+		return &services.Task{Id: "task1", Status: services.SUCCESS, Result: []byte(`{"id":"bm1"}`)}, nil
+	})
+
+	//when
+	_, err := baremetalService.CreateAndTrack(Baremetal{Name: "bm", Password: "s3cr3t"}, BaremetalProvisioningOptions{
+		OnProgress: func(progress BaremetalProvisioningProgress) {},
+	})
+
+	//then
+	assert.NoError(t, err)
+}
+
+func TestSecretStringIsUnmarshalledAsIs(t *testing.T) {
+	//when
+	instance := parseInstance([]byte(`{"id":"i1","password":"s3cr3t"}`))
+
+	//then
+	assert.Equal(t, SecretString("s3cr3t"), instance.Password)
+}
+
+func TestDecryptInstancePassword(t *testing.T) {
+	//given
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, _ := rsa.EncryptPKCS1v15(rand.Reader, &privateKey.PublicKey, []byte("s3cr3t"))
+	pkcs1PEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	pkcs8Bytes, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	pkcs8PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	//when
+	pkcs1Password, pkcs1Err := DecryptInstancePassword(base64.StdEncoding.EncodeToString(ciphertext), pkcs1PEM)
+	pkcs8Password, pkcs8Err := DecryptInstancePassword(base64.StdEncoding.EncodeToString(ciphertext), pkcs8PEM)
+
+	//then
+	assert.NoError(t, pkcs1Err)
+	assert.NoError(t, pkcs8Err)
+	assert.Equal(t, "s3cr3t", pkcs1Password.Reveal())
+	assert.Equal(t, "s3cr3t", pkcs8Password.Reveal())
+}
+
+func TestDecryptInstancePasswordReturnsErrorIfKeyIsInvalid(t *testing.T) {
+	//when
+	_, notBase64Err := DecryptInstancePassword("not base64!", []byte{})
+	_, notPEMErr := DecryptInstancePassword("ZW5jcnlwdGVk", []byte("not a key"))
+
+	//then
+	assert.Error(t, notBase64Err)
+	assert.Error(t, notPEMErr)
+}
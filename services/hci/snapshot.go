@@ -0,0 +1,136 @@
+package hci
+
+import (
+	"encoding/json"
+
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/services"
+)
+
+const (
+	SNAPSHOT_CREATE_VOLUME_OPERATION = "createVolume"
+	SNAPSHOT_REVERT_OPERATION        = "revert"
+)
+
+const (
+	SNAPSHOT_STATE_CREATING   = "Creating"
+	SNAPSHOT_STATE_BACKING_UP = "BackingUp"
+	SNAPSHOT_STATE_BACKED_UP  = "BackedUp"
+	SNAPSHOT_STATE_ERROR      = "Error"
+)
+
+type Snapshot struct {
+	Id         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	VolumeId   string `json:"volumeId,omitempty"`
+	VolumeName string `json:"volumeName,omitempty"`
+	VolumeType string `json:"volumeType,omitempty"`
+	State      string `json:"state,omitempty"`
+	// MANUAL for snapshots created on demand, otherwise the schedule of the snapshot policy that created it
+	IntervalType string `json:"intervalType,omitempty"`
+	Created      string `json:"created,omitempty"`
+	ZoneId       string `json:"zoneId,omitempty"`
+	ZoneName     string `json:"zoneName,omitempty"`
+}
+
+type SnapshotService interface {
+	Get(id string) (*Snapshot, error)
+	List() ([]Snapshot, error)
+	ListOfVolume(volumeId string) ([]Snapshot, error)
+	ListWithOptions(options map[string]string) ([]Snapshot, error)
+	Create(snapshot Snapshot) (*Snapshot, error)
+	Delete(id string) (bool, error)
+	CreateVolume(id string, volume Volume) (*Volume, error)
+	Revert(id string) (bool, error)
+}
+
+type SnapshotApi struct {
+	entityService services.EntityService
+}
+
+func NewSnapshotService(apiClient api.ApiClient, serviceCode string, environmentName string) SnapshotService {
+	return &SnapshotApi{
+		entityService: services.NewEntityService(apiClient, serviceCode, environmentName, SNAPSHOT_ENTITY_TYPE),
+	}
+}
+
+func parseSnapshot(data []byte) *Snapshot {
+	snapshot := Snapshot{}
+	json.Unmarshal(data, &snapshot)
+	return &snapshot
+}
+
+func parseSnapshotList(data []byte) []Snapshot {
+	snapshots := []Snapshot{}
+	json.Unmarshal(data, &snapshots)
+	return snapshots
+}
+
+// Get snapshot with the specified id for the current environment
+func (snapshotApi *SnapshotApi) Get(id string) (*Snapshot, error) {
+	data, err := snapshotApi.entityService.Get(id, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshot(data), nil
+}
+
+// List all snapshots for the current environment
+func (snapshotApi *SnapshotApi) List() ([]Snapshot, error) {
+	return snapshotApi.ListWithOptions(map[string]string{})
+}
+
+// List all snapshots of a volume for the current environment
+func (snapshotApi *SnapshotApi) ListOfVolume(volumeId string) ([]Snapshot, error) {
+	return snapshotApi.ListWithOptions(map[string]string{
+		"volumeId": volumeId,
+	})
+}
+
+// List all snapshots for the current environment. Can use options to do sorting and paging.
+func (snapshotApi *SnapshotApi) ListWithOptions(options map[string]string) ([]Snapshot, error) {
+	data, err := snapshotApi.entityService.List(options)
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshotList(data), nil
+}
+
+// Create a snapshot of the volume with the id specified in the snapshot
+func (snapshotApi *SnapshotApi) Create(snapshot Snapshot) (*Snapshot, error) {
+	send, merr := json.Marshal(snapshot)
+	if merr != nil {
+		return nil, merr
+	}
+	body, err := snapshotApi.entityService.Create(send, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshot(body), nil
+}
+
+// Delete the snapshot with the specified id in the current environment
+func (snapshotApi *SnapshotApi) Delete(id string) (bool, error) {
+	_, err := snapshotApi.entityService.Delete(id, []byte{}, map[string]string{})
+	return err == nil, err
+}
+
+// Create a new volume from the snapshot with the specified id. The name, disk offering and zone of the volume can be specified.
+func (snapshotApi *SnapshotApi) CreateVolume(id string, volume Volume) (*Volume, error) {
+	send, merr := json.Marshal(volume)
+	if merr != nil {
+		return nil, merr
+	}
+	body, err := snapshotApi.entityService.Execute(id, SNAPSHOT_CREATE_VOLUME_OPERATION, send, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseVolume(body), nil
+}
+
+// Revert the volume of the snapshot with the specified id to the state it was in when the snapshot was created
+// Note: Any change made to the volume after the snapshot was created is lost
+func (snapshotApi *SnapshotApi) Revert(id string) (bool, error) {
+	_, err := snapshotApi.entityService.Execute(id, SNAPSHOT_REVERT_OPERATION, []byte{}, map[string]string{})
+	return err == nil, err
+}
@@ -0,0 +1,170 @@
+package hci
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hypertec-cloud/go-hci/api"
+	"github.com/hypertec-cloud/go-hci/services"
+)
+
+// Schedules of a snapshot policy
+const (
+	SNAPSHOT_POLICY_INTERVAL_HOURLY  = "HOURLY"
+	SNAPSHOT_POLICY_INTERVAL_DAILY   = "DAILY"
+	SNAPSHOT_POLICY_INTERVAL_WEEKLY  = "WEEKLY"
+	SNAPSHOT_POLICY_INTERVAL_MONTHLY = "MONTHLY"
+)
+
+// Recurring snapshots of a volume. Once MaxSnapshots is reached, the oldest snapshot of the policy is deleted
+// when a new one is taken.
+type SnapshotPolicy struct {
+	Id           string `json:"id,omitempty"`
+	VolumeId     string `json:"volumeId,omitempty"`
+	VolumeName   string `json:"volumeName,omitempty"`
+	IntervalType string `json:"intervalType,omitempty"`
+	// Minute of the hour the snapshot is taken at
+	Minute int `json:"minute"`
+	// Hour of the day the snapshot is taken at. Ignored by HOURLY policies.
+	Hour int `json:"hour"`
+	// Day of the week the snapshot is taken on, from 1 (Sunday) to 7 (Saturday). Only for WEEKLY policies.
+	DayOfWeek int `json:"dayOfWeek,omitempty"`
+	// Day of the month the snapshot is taken on, from 1 to 28. Only for MONTHLY policies.
+	DayOfMonth int `json:"dayOfMonth,omitempty"`
+	// IANA time zone of the schedule (ex: America/Montreal). Defaults to UTC on the platform.
+	TimeZone     string `json:"timeZone,omitempty"`
+	MaxSnapshots int    `json:"maxSnapshots,omitempty"`
+}
+
+// Check the schedule of the policy is consistent with its interval type.
+// The time zone is left to the platform: the zone database of the host may not know it.
+func (policy *SnapshotPolicy) Validate() error {
+	switch strings.ToUpper(policy.IntervalType) {
+	case SNAPSHOT_POLICY_INTERVAL_HOURLY, SNAPSHOT_POLICY_INTERVAL_DAILY:
+	case SNAPSHOT_POLICY_INTERVAL_WEEKLY:
+		if policy.DayOfWeek < 1 || policy.DayOfWeek > 7 {
+			return fmt.Errorf("Day of week of a weekly snapshot policy must be between 1 and 7, got %d", policy.DayOfWeek)
+		}
+	case SNAPSHOT_POLICY_INTERVAL_MONTHLY:
+		if policy.DayOfMonth < 1 || policy.DayOfMonth > 28 {
+			return fmt.Errorf("Day of month of a monthly snapshot policy must be between 1 and 28, got %d", policy.DayOfMonth)
+		}
+	default:
+		return fmt.Errorf("Invalid snapshot policy interval type %s, must be one of %s, %s, %s or %s", policy.IntervalType,
+			SNAPSHOT_POLICY_INTERVAL_HOURLY, SNAPSHOT_POLICY_INTERVAL_DAILY, SNAPSHOT_POLICY_INTERVAL_WEEKLY, SNAPSHOT_POLICY_INTERVAL_MONTHLY)
+	}
+	if policy.Minute < 0 || policy.Minute > 59 {
+		return fmt.Errorf("Minute of a snapshot policy must be between 0 and 59, got %d", policy.Minute)
+	}
+	if policy.Hour < 0 || policy.Hour > 23 {
+		return fmt.Errorf("Hour of a snapshot policy must be between 0 and 23, got %d", policy.Hour)
+	}
+	if policy.MaxSnapshots < 1 {
+		return fmt.Errorf("Snapshot policy must keep at least 1 snapshot, got %d", policy.MaxSnapshots)
+	}
+	return nil
+}
+
+type SnapshotPolicyService interface {
+	Get(id string) (*SnapshotPolicy, error)
+	List() ([]SnapshotPolicy, error)
+	ListOfVolume(volumeId string) ([]SnapshotPolicy, error)
+	ListWithOptions(options map[string]string) ([]SnapshotPolicy, error)
+	Create(policy SnapshotPolicy) (*SnapshotPolicy, error)
+	Update(policy SnapshotPolicy) (*SnapshotPolicy, error)
+	Delete(id string) (bool, error)
+}
+
+type SnapshotPolicyApi struct {
+	entityService services.EntityService
+}
+
+func NewSnapshotPolicyService(apiClient api.ApiClient, serviceCode string, environmentName string) SnapshotPolicyService {
+	return &SnapshotPolicyApi{
+		entityService: services.NewEntityService(apiClient, serviceCode, environmentName, SNAPSHOT_POLICY_ENTITY_TYPE),
+	}
+}
+
+func parseSnapshotPolicy(data []byte) *SnapshotPolicy {
+	policy := SnapshotPolicy{}
+	json.Unmarshal(data, &policy)
+	return &policy
+}
+
+func parseSnapshotPolicyList(data []byte) []SnapshotPolicy {
+	policies := []SnapshotPolicy{}
+	json.Unmarshal(data, &policies)
+	return policies
+}
+
+// Get snapshot policy with the specified id for the current environment
+func (snapshotPolicyApi *SnapshotPolicyApi) Get(id string) (*SnapshotPolicy, error) {
+	data, err := snapshotPolicyApi.entityService.Get(id, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshotPolicy(data), nil
+}
+
+// List all snapshot policies for the current environment
+func (snapshotPolicyApi *SnapshotPolicyApi) List() ([]SnapshotPolicy, error) {
+	return snapshotPolicyApi.ListWithOptions(map[string]string{})
+}
+
+// List all snapshot policies of a volume for the current environment
+func (snapshotPolicyApi *SnapshotPolicyApi) ListOfVolume(volumeId string) ([]SnapshotPolicy, error) {
+	return snapshotPolicyApi.ListWithOptions(map[string]string{
+		"volumeId": volumeId,
+	})
+}
+
+// List all snapshot policies for the current environment. Can use options to do sorting and paging.
+func (snapshotPolicyApi *SnapshotPolicyApi) ListWithOptions(options map[string]string) ([]SnapshotPolicy, error) {
+	data, err := snapshotPolicyApi.entityService.List(options)
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshotPolicyList(data), nil
+}
+
+// Create a snapshot policy for the volume with the id specified in the policy.
+// The policy is validated before it is sent.
+func (snapshotPolicyApi *SnapshotPolicyApi) Create(policy SnapshotPolicy) (*SnapshotPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	send, merr := json.Marshal(policy)
+	if merr != nil {
+		return nil, merr
+	}
+	body, err := snapshotPolicyApi.entityService.Create(send, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshotPolicy(body), nil
+}
+
+// Update the schedule, time zone and max snapshots of the snapshot policy with the specified id.
+// The policy is validated before it is sent.
+func (snapshotPolicyApi *SnapshotPolicyApi) Update(policy SnapshotPolicy) (*SnapshotPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	send, merr := json.Marshal(policy)
+	if merr != nil {
+		return nil, merr
+	}
+	body, err := snapshotPolicyApi.entityService.Update(policy.Id, send, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshotPolicy(body), nil
+}
+
+// Delete the snapshot policy with the specified id in the current environment.
+// The snapshots already taken by the policy are kept.
+func (snapshotPolicyApi *SnapshotPolicyApi) Delete(id string) (bool, error) {
+	_, err := snapshotPolicyApi.entityService.Delete(id, []byte{}, map[string]string{})
+	return err == nil, err
+}
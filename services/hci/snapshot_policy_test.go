@@ -0,0 +1,140 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const TEST_SNAPSHOT_POLICY_ID = "test_snapshot_policy_id"
+
+func TestSnapshotPolicyValidate(t *testing.T) {
+	assert.NoError(t, (&SnapshotPolicy{IntervalType: "hourly", Minute: 30, MaxSnapshots: 24}).Validate())
+	assert.NoError(t, (&SnapshotPolicy{IntervalType: SNAPSHOT_POLICY_INTERVAL_WEEKLY, Hour: 2, DayOfWeek: 1, TimeZone: "UTC", MaxSnapshots: 4}).Validate())
+	assert.Error(t, (&SnapshotPolicy{IntervalType: "YEARLY", MaxSnapshots: 1}).Validate())
+	assert.Error(t, (&SnapshotPolicy{IntervalType: SNAPSHOT_POLICY_INTERVAL_DAILY, Hour: 24, MaxSnapshots: 1}).Validate())
+	assert.Error(t, (&SnapshotPolicy{IntervalType: SNAPSHOT_POLICY_INTERVAL_WEEKLY, MaxSnapshots: 1}).Validate())
+	assert.Error(t, (&SnapshotPolicy{IntervalType: SNAPSHOT_POLICY_INTERVAL_MONTHLY, DayOfMonth: 31, MaxSnapshots: 1}).Validate())
+	assert.Error(t, (&SnapshotPolicy{IntervalType: SNAPSHOT_POLICY_INTERVAL_DAILY}).Validate())
+}
+
+func TestListOfVolumeSnapshotPolicyReturnPoliciesIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotPolicyService := SnapshotPolicyApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().List(map[string]string{"volumeId": TEST_VOLUME_ID}).Return([]byte(`[{"id":"`+TEST_SNAPSHOT_POLICY_ID+`","volumeId":"`+TEST_VOLUME_ID+`","intervalType":"DAILY","minute":15,"hour":3,"timeZone":"UTC","maxSnapshots":7}]`), nil)
+
+	//when
+	policies, err := snapshotPolicyService.ListOfVolume(TEST_VOLUME_ID)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, []SnapshotPolicy{{
+		Id:           TEST_SNAPSHOT_POLICY_ID,
+		VolumeId:     TEST_VOLUME_ID,
+		IntervalType: SNAPSHOT_POLICY_INTERVAL_DAILY,
+		Minute:       15,
+		Hour:         3,
+		TimeZone:     "UTC",
+		MaxSnapshots: 7,
+	}}, policies)
+}
+
+func TestCreateSnapshotPolicyReturnCreatedPolicyIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotPolicyService := SnapshotPolicyApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"volumeId":"` + TEST_VOLUME_ID + `","intervalType":"MONTHLY","minute":0,"hour":0,"dayOfMonth":1,"maxSnapshots":12}`)
+	mockEntityService.EXPECT().Create(expectedBody, gomock.Any()).Return([]byte(`{"id":"`+TEST_SNAPSHOT_POLICY_ID+`"}`), nil)
+
+	//when
+	policy, err := snapshotPolicyService.Create(SnapshotPolicy{
+		VolumeId:     TEST_VOLUME_ID,
+		IntervalType: SNAPSHOT_POLICY_INTERVAL_MONTHLY,
+		DayOfMonth:   1,
+		MaxSnapshots: 12,
+	})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, TEST_SNAPSHOT_POLICY_ID, policy.Id)
+}
+
+func TestCreateSnapshotPolicyReturnErrorIfInvalid(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotPolicyService := SnapshotPolicyApi{
+		entityService: mockEntityService,
+	}
+
+	//when
+	policy, err := snapshotPolicyService.Create(SnapshotPolicy{VolumeId: TEST_VOLUME_ID, IntervalType: SNAPSHOT_POLICY_INTERVAL_HOURLY})
+
+	//then
+	assert.Nil(t, policy)
+	assert.Error(t, err)
+}
+
+func TestUpdateSnapshotPolicyReturnUpdatedPolicyIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotPolicyService := SnapshotPolicyApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().Update(TEST_SNAPSHOT_POLICY_ID, gomock.Any(), gomock.Any()).Return([]byte(`{"id":"`+TEST_SNAPSHOT_POLICY_ID+`","maxSnapshots":48}`), nil)
+
+	//when
+	policy, err := snapshotPolicyService.Update(SnapshotPolicy{Id: TEST_SNAPSHOT_POLICY_ID, IntervalType: SNAPSHOT_POLICY_INTERVAL_HOURLY, MaxSnapshots: 48})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, 48, policy.MaxSnapshots)
+}
+
+func TestDeleteSnapshotPolicyReturnFalseIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotPolicyService := SnapshotPolicyApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_delete_error"}
+	mockEntityService.EXPECT().Delete(TEST_SNAPSHOT_POLICY_ID, gomock.Any(), gomock.Any()).Return(nil, mockError)
+
+	//when
+	success, err := snapshotPolicyService.Delete(TEST_SNAPSHOT_POLICY_ID)
+
+	//then
+	assert.False(t, success)
+	assert.Equal(t, mockError, err)
+}
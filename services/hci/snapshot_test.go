@@ -0,0 +1,186 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	TEST_SNAPSHOT_ID      = "test_snapshot_id"
+	TEST_SNAPSHOT_NAME    = "test_snapshot"
+	TEST_SNAPSHOT_STATE   = "BackedUp"
+	TEST_SNAPSHOT_CREATED = "2019-03-04T15:04:05Z"
+)
+
+func buildSnapshotJsonResponse(snapshot *Snapshot) []byte {
+	return []byte(`{"id":"` + snapshot.Id + `",` +
+		`"name":"` + snapshot.Name + `",` +
+		`"volumeId":"` + snapshot.VolumeId + `",` +
+		`"state":"` + snapshot.State + `",` +
+		`"created":"` + snapshot.Created + `"}`)
+}
+
+func TestGetSnapshotReturnSnapshotIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotService := SnapshotApi{
+		entityService: mockEntityService,
+	}
+
+	expectedSnapshot := Snapshot{
+		Id:       TEST_SNAPSHOT_ID,
+		Name:     TEST_SNAPSHOT_NAME,
+		VolumeId: TEST_VOLUME_ID,
+		State:    TEST_SNAPSHOT_STATE,
+		Created:  TEST_SNAPSHOT_CREATED,
+	}
+
+	mockEntityService.EXPECT().Get(TEST_SNAPSHOT_ID, gomock.Any()).Return(buildSnapshotJsonResponse(&expectedSnapshot), nil)
+
+	//when
+	snapshot, _ := snapshotService.Get(TEST_SNAPSHOT_ID)
+
+	//then
+	if assert.NotNil(t, snapshot) {
+		assert.Equal(t, expectedSnapshot, *snapshot)
+	}
+}
+
+func TestGetSnapshotReturnNilWithErrorIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotService := SnapshotApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_get_error"}
+	mockEntityService.EXPECT().Get(TEST_SNAPSHOT_ID, gomock.Any()).Return(nil, mockError)
+
+	//when
+	snapshot, err := snapshotService.Get(TEST_SNAPSHOT_ID)
+
+	//then
+	assert.Nil(t, snapshot)
+	assert.Equal(t, mockError, err)
+}
+
+func TestListOfVolumeSnapshotReturnSnapshotsIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotService := SnapshotApi{
+		entityService: mockEntityService,
+	}
+
+	expectedSnapshot := Snapshot{Id: TEST_SNAPSHOT_ID, Name: TEST_SNAPSHOT_NAME, VolumeId: TEST_VOLUME_ID}
+	mockEntityService.EXPECT().List(map[string]string{"volumeId": TEST_VOLUME_ID}).Return([]byte(`[`+string(buildSnapshotJsonResponse(&expectedSnapshot))+`]`), nil)
+
+	//when
+	snapshots, err := snapshotService.ListOfVolume(TEST_VOLUME_ID)
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, []Snapshot{expectedSnapshot}, snapshots)
+}
+
+func TestCreateSnapshotReturnCreatedSnapshotIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotService := SnapshotApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"name":"` + TEST_SNAPSHOT_NAME + `","volumeId":"` + TEST_VOLUME_ID + `"}`)
+	mockEntityService.EXPECT().Create(expectedBody, gomock.Any()).Return([]byte(`{"id":"`+TEST_SNAPSHOT_ID+`"}`), nil)
+
+	//when
+	snapshot, err := snapshotService.Create(Snapshot{Name: TEST_SNAPSHOT_NAME, VolumeId: TEST_VOLUME_ID})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, TEST_SNAPSHOT_ID, snapshot.Id)
+}
+
+func TestDeleteSnapshotReturnTrueIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotService := SnapshotApi{
+		entityService: mockEntityService,
+	}
+
+	mockEntityService.EXPECT().Delete(TEST_SNAPSHOT_ID, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+
+	//when
+	success, err := snapshotService.Delete(TEST_SNAPSHOT_ID)
+
+	//then
+	assert.NoError(t, err)
+	assert.True(t, success)
+}
+
+func TestCreateVolumeFromSnapshotReturnVolumeIfSuccess(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotService := SnapshotApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"name":"restored","diskOfferingId":"offering"}`)
+	mockEntityService.EXPECT().Execute(TEST_SNAPSHOT_ID, SNAPSHOT_CREATE_VOLUME_OPERATION, expectedBody, gomock.Any()).Return([]byte(`{"id":"new_volume","name":"restored"}`), nil)
+
+	//when
+	volume, err := snapshotService.CreateVolume(TEST_SNAPSHOT_ID, Volume{Name: "restored", DiskOfferingId: "offering"})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, Volume{Id: "new_volume", Name: "restored"}, *volume)
+}
+
+func TestRevertSnapshotReturnFalseIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	snapshotService := SnapshotApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_revert_error"}
+	mockEntityService.EXPECT().Execute(TEST_SNAPSHOT_ID, SNAPSHOT_REVERT_OPERATION, gomock.Any(), gomock.Any()).Return(nil, mockError)
+
+	//when
+	success, err := snapshotService.Revert(TEST_SNAPSHOT_ID)
+
+	//then
+	assert.False(t, success)
+	assert.Equal(t, mockError, err)
+}
@@ -7,12 +7,17 @@ import (
 	"github.com/hypertec-cloud/go-hci/services"
 )
 
+const (
+	TEMPLATE_COPY_OPERATION    = "copy"
+	TEMPLATE_EXTRACT_OPERATION = "extract"
+)
+
 type Template struct {
 	ID                string   `json:"id,omitempty"`
 	Name              string   `json:"name,omitempty"`
 	Description       string   `json:"description,omitempty"`
 	Size              int      `json:"size,omitempty"`
-	AvailablePublicly bool     `json:availablePublicly`
+	AvailablePublicly bool     `json:"availablePublicly"`
 	Ready             bool     `json:"ready,omitempty"`
 	SSHKeyEnabled     bool     `json:"sshKeyEnabled,omitempty"`
 	PassowordEnabled  bool     `json:"passwordEnabled,omitempty"`
@@ -26,6 +31,9 @@ type Template struct {
 	URL               string   `json:"url,omitempty"`
 	ZoneID            string   `json:"zoneId,omitempty"`
 	AvailableInZones  []string `json:"availableInZones,omitempty"`
+	// Source of a template created from an existing volume or snapshot
+	VolumeID   string `json:"volumeId,omitempty"`
+	SnapshotID string `json:"snapshotId,omitempty"`
 }
 
 type TemplateService interface {
@@ -33,7 +41,28 @@ type TemplateService interface {
 	List() ([]Template, error)
 	ListWithOptions(options map[string]string) ([]Template, error)
 	Create(Template) (*Template, error)
+	CreateFromVolume(volumeId string, template Template) (*Template, error)
+	CreateFromSnapshot(snapshotId string, template Template) (*Template, error)
+	Update(id string, update TemplateUpdate) (*Template, error)
 	Delete(id string) (bool, error)
+	CopyToZones(id string, zoneIds []string) (*Template, error)
+	Extract(id string) (*ExtractResult, error)
+}
+
+// Changes to the metadata of a template. Empty values and nil flags are left unchanged.
+type TemplateUpdate struct {
+	Name             string `json:"name,omitempty"`
+	Description      string `json:"description,omitempty"`
+	OSTypeID         string `json:"osTypeId,omitempty"`
+	SSHKeyEnabled    *bool  `json:"sshKeyEnabled,omitempty"`
+	PassowordEnabled *bool  `json:"passwordEnabled,omitempty"`
+	Extractable      *bool  `json:"extractable,omitempty"`
+	Resizable        *bool  `json:"resizable,omitempty"`
+}
+
+// Destination of a template copy
+type templateCopy struct {
+	ZoneID string `json:"zoneId"`
 }
 
 type TemplateApi struct {
@@ -93,7 +122,64 @@ func (templateApi *TemplateApi) Create(t Template) (*Template, error) {
 	return parseTemplate(body), nil
 }
 
+// Create a template from the volume with the specified id. The volume must be the OS volume of a stopped instance.
+func (templateApi *TemplateApi) CreateFromVolume(volumeId string, t Template) (*Template, error) {
+	t.VolumeID = volumeId
+	return templateApi.Create(t)
+}
+
+// Create a template from the snapshot with the specified id. The snapshot must be of an OS volume.
+func (templateApi *TemplateApi) CreateFromSnapshot(snapshotId string, t Template) (*Template, error) {
+	t.SnapshotID = snapshotId
+	return templateApi.Create(t)
+}
+
 func (templateApi *TemplateApi) Delete(id string) (bool, error) {
 	_, err := templateApi.entityService.Delete(id, []byte{}, map[string]string{})
 	return err == nil, err
 }
+
+// Update the name, description, OS type and flags of the template with the specified id.
+// Only the values set in the update are changed.
+func (templateApi *TemplateApi) Update(id string, update TemplateUpdate) (*Template, error) {
+	send, merr := json.Marshal(update)
+	if merr != nil {
+		return nil, merr
+	}
+	body, err := templateApi.entityService.Update(id, send, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseTemplate(body), nil
+}
+
+// Copy the template with the specified id to the zones it is not available in yet.
+// Returns the template with the zones it is now available in.
+func (templateApi *TemplateApi) CopyToZones(id string, zoneIds []string) (*Template, error) {
+	template, err := templateApi.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, zoneId := range zoneIds {
+		if containsId(template.AvailableInZones, zoneId) {
+			continue
+		}
+		send, merr := json.Marshal(templateCopy{ZoneID: zoneId})
+		if merr != nil {
+			return nil, merr
+		}
+		if _, err := templateApi.entityService.Execute(id, TEMPLATE_COPY_OPERATION, send, map[string]string{}); err != nil {
+			return nil, err
+		}
+	}
+	return templateApi.Get(id)
+}
+
+// Get a URL to download the template with the specified id. The template must be extractable.
+func (templateApi *TemplateApi) Extract(id string) (*ExtractResult, error) {
+	body, err := templateApi.entityService.Execute(id, TEMPLATE_EXTRACT_OPERATION, []byte{}, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseExtractResult(body), nil
+}
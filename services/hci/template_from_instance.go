@@ -0,0 +1,117 @@
+package hci
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DEFAULT_TEMPLATE_READY_TIMEOUT       = time.Hour
+	DEFAULT_TEMPLATE_READY_POLL_INTERVAL = 30 * time.Second
+)
+
+type TemplateWaitOptions struct {
+	// Wait for the template to be ready before returning it
+	WaitUntilReady bool
+	// Defaults to DEFAULT_TEMPLATE_READY_TIMEOUT
+	Timeout time.Duration
+	// Defaults to DEFAULT_TEMPLATE_READY_POLL_INTERVAL
+	PollInterval time.Duration
+	// Defaults to the system clock
+	Clock Clock
+}
+
+type TemplateFromInstanceOptions struct {
+	// Stop the instance if it is running. Otherwise, the instance must already be stopped.
+	StopInstance bool
+	TemplateWaitOptions
+}
+
+// Returned when a template is still not ready after the timeout. The template may still become ready later.
+type TemplateNotReadyError struct {
+	TemplateId string
+	Timeout    time.Duration
+}
+
+func (e TemplateNotReadyError) Error() string {
+	return "Template " + e.TemplateId + " still not ready after " + e.Timeout.String()
+}
+
+// Create a template from the OS volume of the instance with the specified id.
+// The instance is left stopped: start it again once the template is created if needed.
+func (resources Resources) CreateTemplateFromInstance(instanceId string, template Template, options TemplateFromInstanceOptions) (*Template, error) {
+	instance, err := resources.Instances.Get(instanceId)
+	if err != nil {
+		return nil, err
+	}
+	if !instance.IsStopped() {
+		if !options.StopInstance || !instance.CanStop() {
+			return nil, InvalidInstanceStateError{
+				InstanceId:    instanceId,
+				Operation:     "create a template from",
+				State:         instance.State,
+				AllowedStates: []string{INSTANCE_STATE_STOPPED},
+			}
+		}
+		if _, err := resources.Instances.Stop(instanceId); err != nil {
+			return nil, err
+		}
+	}
+	volumes, err := resources.Volumes.ListOfType(VOLUME_TYPE_OS)
+	if err != nil {
+		return nil, err
+	}
+	osVolumes := volumesOfInstance(volumes, instanceId)
+	if len(osVolumes) == 0 {
+		return nil, fmt.Errorf("No OS volume found for instance %s", instanceId)
+	}
+	return resources.CreateTemplateFromVolume(osVolumes[0].Id, template, options.TemplateWaitOptions)
+}
+
+// Create a template from the volume with the specified id, optionally waiting for it to be ready
+func (resources Resources) CreateTemplateFromVolume(volumeId string, template Template, options TemplateWaitOptions) (*Template, error) {
+	created, err := resources.Templates.CreateFromVolume(volumeId, template)
+	if err != nil {
+		return nil, err
+	}
+	return resources.waitForTemplateReady(created, options)
+}
+
+// Create a template from the snapshot with the specified id, optionally waiting for it to be ready
+func (resources Resources) CreateTemplateFromSnapshot(snapshotId string, template Template, options TemplateWaitOptions) (*Template, error) {
+	created, err := resources.Templates.CreateFromSnapshot(snapshotId, template)
+	if err != nil {
+		return nil, err
+	}
+	return resources.waitForTemplateReady(created, options)
+}
+
+func (resources Resources) waitForTemplateReady(created *Template, options TemplateWaitOptions) (*Template, error) {
+	if !options.WaitUntilReady || created.Ready {
+		return created, nil
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DEFAULT_TEMPLATE_READY_TIMEOUT
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DEFAULT_TEMPLATE_READY_POLL_INTERVAL
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	id := created.ID
+	deadline := options.Clock.Now().Add(options.Timeout)
+	for {
+		template, err := resources.Templates.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if template.Ready {
+			return template, nil
+		}
+		if !options.Clock.Now().Before(deadline) {
+			return nil, TemplateNotReadyError{TemplateId: id, Timeout: options.Timeout}
+		}
+		<-options.Clock.After(options.PollInterval)
+	}
+}
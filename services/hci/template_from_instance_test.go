@@ -0,0 +1,141 @@
+package hci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks/services_mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTemplateFromInstanceStopsInstanceAndWaitsUntilReady(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	gomock.InOrder(
+		mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","state":"Running"}`), nil),
+		mockServices.instances.EXPECT().Execute("i1", INSTANCE_STOP_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil),
+	)
+	mockServices.volumes.EXPECT().List(map[string]string{"type": VOLUME_TYPE_OS}).Return([]byte(`[{"id":"v2","type":"OS","instanceId":"i2"},{"id":"v1","type":"OS","instanceId":"i1"}]`), nil)
+	gomock.InOrder(
+		mockServices.templates.EXPECT().Create([]byte(`{"name":"golden","availablePublicly":false,"volumeId":"v1"}`), gomock.Any()).Return([]byte(`{"id":"t1","name":"golden"}`), nil),
+		mockServices.templates.EXPECT().Get("t1", gomock.Any()).Return([]byte(`{"id":"t1","ready":false}`), nil),
+		mockServices.templates.EXPECT().Get("t1", gomock.Any()).Return([]byte(`{"id":"t1","ready":true}`), nil),
+	)
+
+	//when
+	template, err := resources.CreateTemplateFromInstance("i1", Template{Name: "golden"}, TemplateFromInstanceOptions{
+		StopInstance: true,
+		TemplateWaitOptions: TemplateWaitOptions{
+			WaitUntilReady: true,
+			Clock:          &steppingClock{now: time.Now()},
+		},
+	})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, Template{ID: "t1", Ready: true}, *template)
+	}
+}
+
+func TestCreateTemplateFromInstanceReturnsErrorIfInstanceRunning(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","state":"Running"}`), nil)
+
+	//when
+	template, err := resources.CreateTemplateFromInstance("i1", Template{Name: "golden"}, TemplateFromInstanceOptions{})
+
+	//then
+	assert.Nil(t, template)
+	assert.IsType(t, InvalidInstanceStateError{}, err)
+}
+
+func TestCreateTemplateFromInstanceReturnsErrorIfNotReadyInTime(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","state":"Stopped"}`), nil)
+	mockServices.volumes.EXPECT().List(gomock.Any()).Return([]byte(`[{"id":"v1","type":"OS","instanceId":"i1"}]`), nil)
+	mockServices.templates.EXPECT().Create(gomock.Any(), gomock.Any()).Return([]byte(`{"id":"t1"}`), nil)
+	mockServices.templates.EXPECT().Get("t1", gomock.Any()).Return([]byte(`{"id":"t1","ready":false}`), nil).Times(3)
+
+	//when
+	_, err := resources.CreateTemplateFromInstance("i1", Template{Name: "golden"}, TemplateFromInstanceOptions{
+		TemplateWaitOptions: TemplateWaitOptions{
+			WaitUntilReady: true,
+			Timeout:        time.Hour,
+			PollInterval:   30 * time.Minute,
+			Clock:          &steppingClock{now: time.Now()},
+		},
+	})
+
+	//then
+	assert.Equal(t, TemplateNotReadyError{TemplateId: "t1", Timeout: time.Hour}, err)
+}
+
+func TestCreateTemplateFromVolumeReturnsTemplateWithoutWaiting(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.templates.EXPECT().Create([]byte(`{"name":"golden","availablePublicly":false,"volumeId":"`+TEST_VOLUME_ID+`"}`), gomock.Any()).Return([]byte(`{"id":"t1","ready":false}`), nil)
+
+	//when
+	template, err := resources.CreateTemplateFromVolume(TEST_VOLUME_ID, Template{Name: "golden"}, TemplateWaitOptions{})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, Template{ID: "t1"}, *template)
+	}
+}
+
+func TestCreateTemplateFromSnapshotWaitsUntilReady(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	gomock.InOrder(
+		mockServices.templates.EXPECT().Create([]byte(`{"name":"golden","availablePublicly":false,"snapshotId":"`+TEST_SNAPSHOT_ID+`"}`), gomock.Any()).Return([]byte(`{"id":"t1"}`), nil),
+		mockServices.templates.EXPECT().Get("t1", gomock.Any()).Return([]byte(`{"id":"t1","ready":false}`), nil),
+		mockServices.templates.EXPECT().Get("t1", gomock.Any()).Return([]byte(`{"id":"t1","ready":true}`), nil),
+	)
+
+	//when
+	template, err := resources.CreateTemplateFromSnapshot(TEST_SNAPSHOT_ID, Template{Name: "golden"}, TemplateWaitOptions{
+		WaitUntilReady: true,
+		Clock:          &steppingClock{now: time.Now()},
+	})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, Template{ID: "t1", Ready: true}, *template)
+	}
+}
+
+func TestCreateTemplateFromSnapshotSendsSnapshotId(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTemplates := services_mocks.NewMockEntityService(ctrl)
+	templateService := TemplateApi{entityService: mockTemplates}
+	mockTemplates.EXPECT().Create([]byte(`{"name":"golden","availablePublicly":false,"snapshotId":"`+TEST_SNAPSHOT_ID+`"}`), gomock.Any()).Return([]byte(`{"id":"t1"}`), nil)
+
+	//when
+	template, err := templateService.CreateFromSnapshot(TEST_SNAPSHOT_ID, Template{Name: "golden"})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "t1", template.ID)
+}
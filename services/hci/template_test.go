@@ -192,3 +192,95 @@ func TestListTemplateReturnNilWithErrorIfError(t *testing.T) {
 	assert.Equal(t, mockError, err)
 
 }
+
+func TestUpdateTemplateSendsOnlyChangedValues(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	templateService := TemplateApi{
+		entityService: mockEntityService,
+	}
+
+	expectedBody := []byte(`{"name":"renamed"}`)
+	mockEntityService.EXPECT().Update(TEST_TEMPLATE_ID, expectedBody, gomock.Any()).Return([]byte(`{"id":"`+TEST_TEMPLATE_ID+`","name":"renamed","sshKeyEnabled":true}`), nil)
+
+	//when
+	template, err := templateService.Update(TEST_TEMPLATE_ID, TemplateUpdate{Name: "renamed"})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", template.Name)
+	assert.True(t, template.SSHKeyEnabled)
+}
+
+func TestUpdateTemplateCanTurnFlagsOff(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	templateService := TemplateApi{
+		entityService: mockEntityService,
+	}
+
+	disabled := false
+	expectedBody := []byte(`{"passwordEnabled":false,"extractable":false}`)
+	mockEntityService.EXPECT().Update(TEST_TEMPLATE_ID, expectedBody, gomock.Any()).Return([]byte(`{"id":"`+TEST_TEMPLATE_ID+`"}`), nil)
+
+	//when
+	_, err := templateService.Update(TEST_TEMPLATE_ID, TemplateUpdate{PassowordEnabled: &disabled, Extractable: &disabled})
+
+	//then
+	assert.NoError(t, err)
+}
+
+func TestCopyTemplateToZonesSkipsZonesItIsAvailableIn(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	templateService := TemplateApi{
+		entityService: mockEntityService,
+	}
+
+	gomock.InOrder(
+		mockEntityService.EXPECT().Get(TEST_TEMPLATE_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_TEMPLATE_ID+`","availableInZones":["zone1"]}`), nil),
+		mockEntityService.EXPECT().Execute(TEST_TEMPLATE_ID, TEMPLATE_COPY_OPERATION, []byte(`{"zoneId":"zone2"}`), gomock.Any()).Return([]byte(`{}`), nil),
+		mockEntityService.EXPECT().Get(TEST_TEMPLATE_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_TEMPLATE_ID+`","availableInZones":["zone1","zone2"]}`), nil),
+	)
+
+	//when
+	template, err := templateService.CopyToZones(TEST_TEMPLATE_ID, []string{"zone1", "zone2"})
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zone1", "zone2"}, template.AvailableInZones)
+}
+
+func TestExtractTemplateReturnErrorIfError(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+
+	templateService := TemplateApi{
+		entityService: mockEntityService,
+	}
+
+	mockError := mocks.MockError{Message: "some_extract_error"}
+	mockEntityService.EXPECT().Execute(TEST_TEMPLATE_ID, TEMPLATE_EXTRACT_OPERATION, gomock.Any(), gomock.Any()).Return(nil, mockError)
+
+	//when
+	result, err := templateService.Extract(TEST_TEMPLATE_ID)
+
+	//then
+	assert.Nil(t, result)
+	assert.Equal(t, mockError, err)
+}
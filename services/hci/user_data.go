@@ -0,0 +1,260 @@
+package hci
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// Maximum size, in bytes, of the user data of an instance once encoded
+const USER_DATA_MAX_SIZE = 32768
+
+const (
+	CLOUD_CONFIG_HEADER       = "#cloud-config"
+	CLOUD_CONFIG_CONTENT_TYPE = "text/cloud-config"
+	SHELL_SCRIPT_CONTENT_TYPE = "text/x-shellscript"
+	USER_DATA_MIME_BOUNDARY   = "==hci-user-data-boundary=="
+)
+
+// Name of the user to add to the users of a cloud-config to keep the default user of the template
+const CLOUD_CONFIG_DEFAULT_USER = "default"
+
+// A user created by cloud-init. Use CLOUD_CONFIG_DEFAULT_USER as name to keep the default user of the template.
+type CloudConfigUser struct {
+	Name              string
+	Groups            []string
+	Shell             string
+	Sudo              string
+	SSHAuthorizedKeys []string
+}
+
+// A file written by cloud-init
+type CloudConfigFile struct {
+	Path        string
+	Content     string
+	Permissions string
+	Owner       string
+}
+
+// Composes the user data of an instance from cloud-config sections and shell scripts.
+// A cloud-config alone or a single shell script is used as is; anything else is combined in a multipart MIME archive.
+type UserDataBuilder struct {
+	users         []CloudConfigUser
+	packageUpdate bool
+	packages      []string
+	files         []CloudConfigFile
+	commands      []string
+	scripts       []string
+	gzip          bool
+	base64        bool
+	maxSize       int
+}
+
+func NewUserDataBuilder() *UserDataBuilder {
+	return &UserDataBuilder{maxSize: USER_DATA_MAX_SIZE}
+}
+
+func (builder *UserDataBuilder) AddUser(user CloudConfigUser) *UserDataBuilder {
+	builder.users = append(builder.users, user)
+	return builder
+}
+
+// Update the package database before installing the packages
+func (builder *UserDataBuilder) SetPackageUpdate(packageUpdate bool) *UserDataBuilder {
+	builder.packageUpdate = packageUpdate
+	return builder
+}
+
+func (builder *UserDataBuilder) AddPackages(packages ...string) *UserDataBuilder {
+	builder.packages = append(builder.packages, packages...)
+	return builder
+}
+
+func (builder *UserDataBuilder) AddFile(file CloudConfigFile) *UserDataBuilder {
+	builder.files = append(builder.files, file)
+	return builder
+}
+
+// Add commands run by cloud-init on first boot, after the packages are installed and the files written
+func (builder *UserDataBuilder) AddRunCmd(commands ...string) *UserDataBuilder {
+	builder.commands = append(builder.commands, commands...)
+	return builder
+}
+
+// Add a script run on first boot. It must start with a shebang line (ex: #!/bin/bash).
+func (builder *UserDataBuilder) AddShellScript(script string) *UserDataBuilder {
+	builder.scripts = append(builder.scripts, script)
+	return builder
+}
+
+// Gzip the user data. Compressed user data is always base64 encoded.
+func (builder *UserDataBuilder) WithGzip() *UserDataBuilder {
+	builder.gzip = true
+	builder.base64 = true
+	return builder
+}
+
+func (builder *UserDataBuilder) WithBase64() *UserDataBuilder {
+	builder.base64 = true
+	return builder
+}
+
+// Change the maximum size of the encoded user data. Defaults to USER_DATA_MAX_SIZE.
+func (builder *UserDataBuilder) WithMaxSize(maxSize int) *UserDataBuilder {
+	builder.maxSize = maxSize
+	return builder
+}
+
+func (builder *UserDataBuilder) hasCloudConfig() bool {
+	return len(builder.users) > 0 || builder.packageUpdate || len(builder.packages) > 0 || len(builder.files) > 0 || len(builder.commands) > 0
+}
+
+// Render the cloud-config document, or an empty string if no cloud-config section was added
+func (builder *UserDataBuilder) CloudConfig() string {
+	if !builder.hasCloudConfig() {
+		return ""
+	}
+	var doc strings.Builder
+	doc.WriteString(CLOUD_CONFIG_HEADER + "\n")
+	if builder.packageUpdate {
+		doc.WriteString("package_update: true\n")
+	}
+	writeYamlList(&doc, "packages", builder.packages, "")
+	if len(builder.users) > 0 {
+		doc.WriteString("users:\n")
+		for _, user := range builder.users {
+			if user.Name == CLOUD_CONFIG_DEFAULT_USER {
+				doc.WriteString("  - " + CLOUD_CONFIG_DEFAULT_USER + "\n")
+				continue
+			}
+			doc.WriteString("  - name: " + yamlString(user.Name) + "\n")
+			writeYamlList(&doc, "groups", user.Groups, "    ")
+			writeYamlValue(&doc, "shell", user.Shell, "    ")
+			writeYamlValue(&doc, "sudo", user.Sudo, "    ")
+			writeYamlList(&doc, "ssh_authorized_keys", user.SSHAuthorizedKeys, "    ")
+		}
+	}
+	if len(builder.files) > 0 {
+		doc.WriteString("write_files:\n")
+		for _, file := range builder.files {
+			doc.WriteString("  - path: " + yamlString(file.Path) + "\n")
+			doc.WriteString("    content: " + yamlString(file.Content) + "\n")
+			writeYamlValue(&doc, "permissions", file.Permissions, "    ")
+			writeYamlValue(&doc, "owner", file.Owner, "    ")
+		}
+	}
+	writeYamlList(&doc, "runcmd", builder.commands, "")
+	return doc.String()
+}
+
+// Build the user data, ready to be set in Instance.UserData.
+// Returns an error if nothing was added, if a script has no shebang or if the encoded user data is too large.
+func (builder *UserDataBuilder) Build() (string, error) {
+	raw, err := builder.buildRaw()
+	if err != nil {
+		return "", err
+	}
+	userData := raw
+	if builder.gzip {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write([]byte(raw)); err != nil {
+			return "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", err
+		}
+		userData = compressed.String()
+	}
+	if builder.base64 {
+		userData = base64.StdEncoding.EncodeToString([]byte(userData))
+	}
+	if builder.maxSize > 0 && len(userData) > builder.maxSize {
+		return "", fmt.Errorf("User data is %d bytes once encoded, the maximum is %d bytes", len(userData), builder.maxSize)
+	}
+	return userData, nil
+}
+
+func (builder *UserDataBuilder) buildRaw() (string, error) {
+	for i, script := range builder.scripts {
+		if !strings.HasPrefix(script, "#!") {
+			return "", fmt.Errorf("Shell script %d must start with a shebang line", i)
+		}
+	}
+	cloudConfig := builder.CloudConfig()
+	switch {
+	case cloudConfig == "" && len(builder.scripts) == 0:
+		return "", fmt.Errorf("User data is empty")
+	case len(builder.scripts) == 0:
+		return cloudConfig, nil
+	case cloudConfig == "" && len(builder.scripts) == 1:
+		return builder.scripts[0], nil
+	}
+
+	var archive bytes.Buffer
+	archive.WriteString("Content-Type: multipart/mixed; boundary=\"" + USER_DATA_MIME_BOUNDARY + "\"\n")
+	archive.WriteString("MIME-Version: 1.0\n\n")
+	writer := multipart.NewWriter(&archive)
+	if err := writer.SetBoundary(USER_DATA_MIME_BOUNDARY); err != nil {
+		return "", err
+	}
+	parts := []string{}
+	contentTypes := []string{}
+	if cloudConfig != "" {
+		parts = append(parts, cloudConfig)
+		contentTypes = append(contentTypes, CLOUD_CONFIG_CONTENT_TYPE)
+	}
+	for _, script := range builder.scripts {
+		parts = append(parts, script)
+		contentTypes = append(contentTypes, SHELL_SCRIPT_CONTENT_TYPE)
+	}
+	for i, part := range parts {
+		if strings.Contains(part, USER_DATA_MIME_BOUNDARY) {
+			return "", fmt.Errorf("User data part %d contains the MIME boundary", i)
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", contentTypes[i]+"; charset=\"utf-8\"")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"part-%03d\"", i+1))
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if _, err := partWriter.Write([]byte(part)); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return archive.String(), nil
+}
+
+// Quote a string for YAML. JSON strings are valid YAML scalars.
+func yamlString(value string) string {
+	var quoted bytes.Buffer
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(quoted.String(), "\n")
+}
+
+func writeYamlValue(doc *strings.Builder, key string, value string, indent string) {
+	if value != "" {
+		doc.WriteString(indent + key + ": " + yamlString(value) + "\n")
+	}
+}
+
+func writeYamlList(doc *strings.Builder, key string, values []string, indent string) {
+	if len(values) == 0 {
+		return
+	}
+	doc.WriteString(indent + key + ":\n")
+	for _, value := range values {
+		doc.WriteString(indent + "  - " + yamlString(value) + "\n")
+	}
+}
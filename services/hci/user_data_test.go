@@ -0,0 +1,139 @@
+package hci
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserDataBuilderRendersCloudConfig(t *testing.T) {
+	//given
+	builder := NewUserDataBuilder().
+		SetPackageUpdate(true).
+		AddPackages("nginx").
+		AddUser(CloudConfigUser{Name: CLOUD_CONFIG_DEFAULT_USER}).
+		AddUser(CloudConfigUser{Name: "deploy", Groups: []string{"sudo"}, Shell: "/bin/bash", SSHAuthorizedKeys: []string{"ssh-rsa AAAA deploy@host"}}).
+		AddFile(CloudConfigFile{Path: "/etc/motd", Content: "Welcome\n<managed>", Permissions: "0644"}).
+		AddRunCmd("systemctl restart nginx")
+
+	//when
+	userData, err := builder.Build()
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, `#cloud-config
+package_update: true
+packages:
+  - "nginx"
+users:
+  - default
+  - name: "deploy"
+    groups:
+      - "sudo"
+    shell: "/bin/bash"
+    ssh_authorized_keys:
+      - "ssh-rsa AAAA deploy@host"
+write_files:
+  - path: "/etc/motd"
+    content: "Welcome\n<managed>"
+    permissions: "0644"
+runcmd:
+  - "systemctl restart nginx"
+`, userData)
+}
+
+func TestUserDataBuilderUsesSingleScriptAsIs(t *testing.T) {
+	//when
+	userData, err := NewUserDataBuilder().AddShellScript("#!/bin/sh\necho hello\n").Build()
+
+	//then
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\necho hello\n", userData)
+}
+
+func TestUserDataBuilderCombinesCloudConfigAndScriptsInMultipartArchive(t *testing.T) {
+	//given
+	builder := NewUserDataBuilder().
+		AddPackages("git").
+		AddShellScript("#!/bin/sh\necho hello\n")
+
+	//when
+	userData, err := builder.Build()
+
+	//then
+	if !assert.NoError(t, err) {
+		return
+	}
+	message, err := mail.ReadMessage(strings.NewReader(userData))
+	if !assert.NoError(t, err) {
+		return
+	}
+	mediaType, params, _ := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	assert.Equal(t, "multipart/mixed", mediaType)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	contentTypes := []string{}
+	contents := []string{}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		content, _ := ioutil.ReadAll(part)
+		contentTypes = append(contentTypes, strings.Split(part.Header.Get("Content-Type"), ";")[0])
+		contents = append(contents, string(content))
+	}
+	assert.Equal(t, []string{CLOUD_CONFIG_CONTENT_TYPE, SHELL_SCRIPT_CONTENT_TYPE}, contentTypes)
+	assert.Equal(t, []string{"#cloud-config\npackages:\n  - \"git\"\n", "#!/bin/sh\necho hello\n"}, contents)
+}
+
+func TestUserDataBuilderGzipsAndEncodesInBase64(t *testing.T) {
+	//when
+	userData, err := NewUserDataBuilder().AddRunCmd("reboot").WithGzip().Build()
+
+	//then
+	if !assert.NoError(t, err) {
+		return
+	}
+	compressed, err := base64.StdEncoding.DecodeString(userData)
+	if !assert.NoError(t, err) {
+		return
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if !assert.NoError(t, err) {
+		return
+	}
+	raw, _ := ioutil.ReadAll(reader)
+	assert.Equal(t, "#cloud-config\nruncmd:\n  - \"reboot\"\n", string(raw))
+}
+
+func TestUserDataBuilderReturnsErrorIfTooLarge(t *testing.T) {
+	//when
+	_, err := NewUserDataBuilder().AddRunCmd(strings.Repeat("x", 100)).WithMaxSize(64).Build()
+
+	//then
+	assert.Error(t, err)
+}
+
+func TestUserDataBuilderReturnsErrorIfScriptHasNoShebang(t *testing.T) {
+	//when
+	_, err := NewUserDataBuilder().AddShellScript("echo hello").Build()
+
+	//then
+	assert.Error(t, err)
+}
+
+func TestUserDataBuilderReturnsErrorIfEmpty(t *testing.T) {
+	//when
+	_, err := NewUserDataBuilder().Build()
+
+	//then
+	assert.Error(t, err)
+}
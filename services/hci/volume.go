@@ -2,6 +2,8 @@ package hci
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/hypertec-cloud/go-hci/api"
 	"github.com/hypertec-cloud/go-hci/services"
@@ -12,6 +14,24 @@ const (
 	VOLUME_TYPE_DATA = "DATA"
 )
 
+const (
+	VOLUME_RESIZE_OPERATION               = "resize"
+	VOLUME_ATTACH_TO_INSTANCE_OPERATION   = "attachToInstance"
+	VOLUME_DETACH_FROM_INSTANCE_OPERATION = "detachFromInstance"
+	VOLUME_UPLOAD_OPERATION               = "upload"
+	VOLUME_EXTRACT_OPERATION              = "extract"
+)
+
+// Disk image formats of uploaded volumes
+const (
+	VOLUME_FORMAT_QCOW2 = "QCOW2"
+	VOLUME_FORMAT_RAW   = "RAW"
+	VOLUME_FORMAT_VHD   = "VHD"
+	VOLUME_FORMAT_VHDX  = "VHDX"
+	VOLUME_FORMAT_VMDK  = "VMDK"
+	VOLUME_FORMAT_OVA   = "OVA"
+)
+
 type Volume struct {
 	Id               string `json:"id,omitempty"`
 	Name             string `json:"name,omitempty"`
@@ -31,6 +51,33 @@ type Volume struct {
 	Iops             int    `json:"iops,omitempty"`
 }
 
+// Disk image to upload as a new data volume. The image is downloaded by the platform from the URL.
+type VolumeUpload struct {
+	Name           string `json:"name,omitempty"`
+	ZoneId         string `json:"zoneId,omitempty"`
+	DiskOfferingId string `json:"diskOfferingId,omitempty"`
+	Url            string `json:"url,omitempty"`
+	Format         string `json:"format,omitempty"`
+	// Checksum of the image, prefixed by its algorithm (ex: {MD5}d41d8cd98f00b204e9800998ecf8427e). Optional.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Check the upload has a name, an http(s) URL and a supported format
+func (upload *VolumeUpload) Validate() error {
+	if upload.Name == "" {
+		return fmt.Errorf("Name of the uploaded volume is required")
+	}
+	if !strings.HasPrefix(strings.ToLower(upload.Url), "http://") && !strings.HasPrefix(strings.ToLower(upload.Url), "https://") {
+		return fmt.Errorf("Invalid volume upload URL %s, must be an http or https URL", upload.Url)
+	}
+	for _, format := range []string{VOLUME_FORMAT_QCOW2, VOLUME_FORMAT_RAW, VOLUME_FORMAT_VHD, VOLUME_FORMAT_VHDX, VOLUME_FORMAT_VMDK, VOLUME_FORMAT_OVA} {
+		if strings.EqualFold(upload.Format, format) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid volume upload format %s", upload.Format)
+}
+
 type VolumeService interface {
 	Get(id string) (*Volume, error)
 	List() ([]Volume, error)
@@ -41,6 +88,8 @@ type VolumeService interface {
 	Delete(string) error
 	AttachToInstance(*Volume, string) error
 	DetachFromInstance(*Volume) error
+	Upload(VolumeUpload) (*Volume, error)
+	Extract(id string) (*ExtractResult, error)
 }
 
 type VolumeApi struct {
@@ -117,7 +166,7 @@ func (api *VolumeApi) Resize(volume *Volume) error {
 	if err != nil {
 		return err
 	}
-	_, err = api.entityService.Execute(volume.Id, "resize", body, map[string]string{})
+	_, err = api.entityService.Execute(volume.Id, VOLUME_RESIZE_OPERATION, body, map[string]string{})
 	return err
 }
 
@@ -128,11 +177,37 @@ func (api *VolumeApi) AttachToInstance(volume *Volume, instanceId string) error
 	if err != nil {
 		return err
 	}
-	_, err = api.entityService.Execute(volume.Id, "attachToInstance", body, map[string]string{})
+	_, err = api.entityService.Execute(volume.Id, VOLUME_ATTACH_TO_INSTANCE_OPERATION, body, map[string]string{})
 	return err
 }
 
 func (api *VolumeApi) DetachFromInstance(volume *Volume) error {
-	_, err := api.entityService.Execute(volume.Id, "detachFromInstance", []byte{}, map[string]string{})
+	_, err := api.entityService.Execute(volume.Id, VOLUME_DETACH_FROM_INSTANCE_OPERATION, []byte{}, map[string]string{})
 	return err
 }
+
+// Upload a disk image from a URL as a new volume. The upload is validated before it is sent.
+// Returns the volume once the platform has downloaded the image.
+func (api *VolumeApi) Upload(upload VolumeUpload) (*Volume, error) {
+	if err := upload.Validate(); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(upload)
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.entityService.Create(body, map[string]string{"operation": VOLUME_UPLOAD_OPERATION})
+	if err != nil {
+		return nil, err
+	}
+	return parseVolume(res), nil
+}
+
+// Get a URL to download the disk image of the volume with the specified id
+func (api *VolumeApi) Extract(id string) (*ExtractResult, error) {
+	res, err := api.entityService.Execute(id, VOLUME_EXTRACT_OPERATION, []byte{}, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return parseExtractResult(res), nil
+}
@@ -0,0 +1,103 @@
+package hci
+
+import (
+	"fmt"
+	"strings"
+)
+
+// States in which volumes can be attached to or detached from an instance
+var volumeAttachmentInstanceStates = []string{INSTANCE_STATE_RUNNING, INSTANCE_STATE_STOPPED}
+
+// Attach the volume with the specified id to the instance with the specified id. The instance must be Running or Stopped.
+// Returns the volume once attached.
+func (resources Resources) AttachVolume(volumeId string, instanceId string) (*Volume, error) {
+	volume, err := resources.Volumes.Get(volumeId)
+	if err != nil {
+		return nil, err
+	}
+	if volume.InstanceId == instanceId {
+		return volume, nil
+	}
+	if volume.InstanceId != "" {
+		return nil, fmt.Errorf("Volume %s is already attached to instance %s, use MoveVolume to attach it to another instance", volumeId, volume.InstanceId)
+	}
+	if err := resources.checkVolumeAttachmentState(instanceId, "attach a volume to"); err != nil {
+		return nil, err
+	}
+	if err := resources.Volumes.AttachToInstance(volume, instanceId); err != nil {
+		return nil, err
+	}
+	return resources.Volumes.Get(volumeId)
+}
+
+// Detach the volume with the specified id from its instance. The instance must be Running or Stopped.
+// Returns the volume once detached.
+func (resources Resources) DetachVolume(volumeId string) (*Volume, error) {
+	volume, err := resources.Volumes.Get(volumeId)
+	if err != nil {
+		return nil, err
+	}
+	if volume.InstanceId == "" {
+		return volume, nil
+	}
+	if err := resources.detachVolume(volume); err != nil {
+		return nil, err
+	}
+	return resources.Volumes.Get(volumeId)
+}
+
+// Detach the volume with the specified id from its instance, if any, and attach it to the instance with the specified id.
+// Both instances must be Running or Stopped. Returns the volume once attached to the new instance.
+func (resources Resources) MoveVolume(volumeId string, instanceId string) (*Volume, error) {
+	volume, err := resources.Volumes.Get(volumeId)
+	if err != nil {
+		return nil, err
+	}
+	if volume.InstanceId == instanceId {
+		return volume, nil
+	}
+	if err := resources.checkVolumeAttachmentState(instanceId, "attach a volume to"); err != nil {
+		return nil, err
+	}
+	previousInstanceId := volume.InstanceId
+	if previousInstanceId != "" {
+		if err := resources.detachVolume(volume); err != nil {
+			return nil, err
+		}
+	}
+	if err := resources.Volumes.AttachToInstance(volume, instanceId); err != nil {
+		if previousInstanceId != "" {
+			return nil, fmt.Errorf("Volume %s was detached from instance %s but could not be attached to instance %s: %s", volumeId, previousInstanceId, instanceId, err)
+		}
+		return nil, err
+	}
+	return resources.Volumes.Get(volumeId)
+}
+
+func (resources Resources) detachVolume(volume *Volume) error {
+	if strings.EqualFold(volume.Type, VOLUME_TYPE_OS) {
+		return fmt.Errorf("Volume %s is the OS volume of instance %s and cannot be detached", volume.Id, volume.InstanceId)
+	}
+	if err := resources.checkVolumeAttachmentState(volume.InstanceId, "detach a volume from"); err != nil {
+		return err
+	}
+	return resources.Volumes.DetachFromInstance(volume)
+}
+
+func (resources Resources) checkVolumeAttachmentState(instanceId string, operation string) error {
+	instance, err := resources.Instances.Get(instanceId)
+	if err != nil {
+		return err
+	}
+	for _, state := range volumeAttachmentInstanceStates {
+		if strings.EqualFold(instance.State, state) {
+			return nil
+		}
+	}
+	return InvalidInstanceStateError{
+		InstanceId:    instanceId,
+		Operation:     operation,
+		State:         instance.State,
+		AllowedStates: volumeAttachmentInstanceStates,
+	}
+}
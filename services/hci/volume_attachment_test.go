@@ -0,0 +1,112 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hypertec-cloud/go-hci/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachVolumeReturnsAttachedVolume(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","state":"Stopped"}`), nil)
+	gomock.InOrder(
+		mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","type":"DATA"}`), nil),
+		mockServices.volumes.EXPECT().Execute(TEST_VOLUME_ID, VOLUME_ATTACH_TO_INSTANCE_OPERATION, []byte(`{"instanceId":"i1"}`), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","type":"DATA","instanceId":"i1","state":"Ready"}`), nil),
+	)
+
+	//when
+	volume, err := resources.AttachVolume(TEST_VOLUME_ID, "i1")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, Volume{Id: TEST_VOLUME_ID, Type: VOLUME_TYPE_DATA, InstanceId: "i1", State: "Ready"}, *volume)
+	}
+}
+
+func TestAttachVolumeReturnsErrorIfInstanceStateDoesNotAllowIt(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","type":"DATA"}`), nil)
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","state":"Stopping"}`), nil)
+
+	//when
+	volume, err := resources.AttachVolume(TEST_VOLUME_ID, "i1")
+
+	//then
+	assert.Nil(t, volume)
+	assert.Equal(t, InvalidInstanceStateError{
+		InstanceId:    "i1",
+		Operation:     "attach a volume to",
+		State:         INSTANCE_STATE_STOPPING,
+		AllowedStates: []string{INSTANCE_STATE_RUNNING, INSTANCE_STATE_STOPPED},
+	}, err)
+}
+
+func TestDetachVolumeRefusesOsVolume(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","type":"OS","instanceId":"i1"}`), nil)
+
+	//when
+	volume, err := resources.DetachVolume(TEST_VOLUME_ID)
+
+	//then
+	assert.Nil(t, volume)
+	assert.Error(t, err)
+}
+
+func TestMoveVolumeDetachesThenAttachesToNewInstance(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.instances.EXPECT().Get("i2", gomock.Any()).Return([]byte(`{"id":"i2","state":"Running"}`), nil)
+	mockServices.instances.EXPECT().Get("i1", gomock.Any()).Return([]byte(`{"id":"i1","state":"Stopped"}`), nil)
+	gomock.InOrder(
+		mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","type":"DATA","instanceId":"i1"}`), nil),
+		mockServices.volumes.EXPECT().Execute(TEST_VOLUME_ID, VOLUME_DETACH_FROM_INSTANCE_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.volumes.EXPECT().Execute(TEST_VOLUME_ID, VOLUME_ATTACH_TO_INSTANCE_OPERATION, []byte(`{"instanceId":"i2"}`), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","type":"DATA","instanceId":"i2"}`), nil),
+	)
+
+	//when
+	volume, err := resources.MoveVolume(TEST_VOLUME_ID, "i2")
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, "i2", volume.InstanceId)
+	}
+}
+
+func TestMoveVolumeReportsDetachedVolumeIfAttachFails(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.instances.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]byte(`{"state":"Running"}`), nil).Times(2)
+	mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","type":"DATA","instanceId":"i1"}`), nil)
+	mockServices.volumes.EXPECT().Execute(TEST_VOLUME_ID, VOLUME_DETACH_FROM_INSTANCE_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{}`), nil)
+	mockServices.volumes.EXPECT().Execute(TEST_VOLUME_ID, VOLUME_ATTACH_TO_INSTANCE_OPERATION, gomock.Any(), gomock.Any()).Return(nil, mocks.MockError{Message: "attach error"})
+
+	//when
+	volume, err := resources.MoveVolume(TEST_VOLUME_ID, "i2")
+
+	//then
+	assert.Nil(t, volume)
+	assert.EqualError(t, err, "Volume "+TEST_VOLUME_ID+" was detached from instance i1 but could not be attached to instance i2: attach error")
+}
@@ -0,0 +1,71 @@
+package hci
+
+import (
+	"fmt"
+)
+
+// Change to apply to a volume with ResizeVolume. Zero values keep the current setting of the volume.
+type VolumeResizeOptions struct {
+	GbSize int
+	Iops   int
+	// Switch the volume to this disk offering. The new size and IOPS are validated against it.
+	DiskOfferingId string
+	// Resizing to a smaller size loses the data past the new size and is refused unless allowed
+	AllowShrink bool
+}
+
+// Resize the volume with the specified id and/or change its IOPS or disk offering.
+// The change is validated against the disk offering of the volume before it is sent.
+// Returns the volume once resized.
+func (resources Resources) ResizeVolume(id string, options VolumeResizeOptions) (*Volume, error) {
+	if options.GbSize == 0 && options.Iops == 0 && options.DiskOfferingId == "" {
+		return nil, fmt.Errorf("Nothing to change on volume %s, specify a size, IOPS or disk offering", id)
+	}
+	volume, err := resources.Volumes.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	diskOfferingId := options.DiskOfferingId
+	if diskOfferingId == "" {
+		diskOfferingId = volume.DiskOfferingId
+	}
+	diskOffering, err := resources.DiskOfferings.Get(diskOfferingId)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateVolumeResize(volume, diskOffering, options); err != nil {
+		return nil, err
+	}
+	err = resources.Volumes.Resize(&Volume{
+		Id:             id,
+		GbSize:         options.GbSize,
+		Iops:           options.Iops,
+		DiskOfferingId: options.DiskOfferingId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources.Volumes.Get(id)
+}
+
+func validateVolumeResize(volume *Volume, diskOffering *DiskOffering, options VolumeResizeOptions) error {
+	newGbSize := options.GbSize
+	if newGbSize != 0 && !diskOffering.CustomSize && newGbSize != diskOffering.GbSize {
+		return fmt.Errorf("Disk offering %s has a fixed size of %d GB, cannot resize volume %s to %d GB", diskOffering.Name, diskOffering.GbSize, volume.Id, newGbSize)
+	}
+	if newGbSize == 0 && options.DiskOfferingId != "" && !diskOffering.CustomSize {
+		newGbSize = diskOffering.GbSize
+	}
+	if newGbSize != 0 && newGbSize < volume.GbSize && !options.AllowShrink {
+		return fmt.Errorf("Shrinking volume %s from %d GB to %d GB loses data and must be explicitly allowed", volume.Id, volume.GbSize, newGbSize)
+	}
+	if options.Iops != 0 {
+		if !diskOffering.CustomIops {
+			return fmt.Errorf("Disk offering %s does not allow custom IOPS", diskOffering.Name)
+		}
+		if options.Iops < diskOffering.MinIops || (diskOffering.MaxIops != 0 && options.Iops > diskOffering.MaxIops) {
+			return fmt.Errorf("IOPS of disk offering %s must be between %d and %d, got %d", diskOffering.Name, diskOffering.MinIops, diskOffering.MaxIops, options.Iops)
+		}
+	}
+	return nil
+}
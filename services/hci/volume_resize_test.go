@@ -0,0 +1,78 @@
+package hci
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeVolumeSendsNewSizeAndIops(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	gomock.InOrder(
+		mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","sizeInGb":50,"diskOfferingId":"custom"}`), nil),
+		mockServices.volumes.EXPECT().Execute(TEST_VOLUME_ID, "resize", []byte(`{"id":"`+TEST_VOLUME_ID+`","sizeInGb":100,"iops":1000}`), gomock.Any()).Return([]byte(`{}`), nil),
+		mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","sizeInGb":100,"iops":1000,"diskOfferingId":"custom"}`), nil),
+	)
+	mockServices.diskOfferings.EXPECT().Get("custom", gomock.Any()).Return([]byte(`{"id":"custom","customSize":true,"customIops":true,"minIops":500,"maxIops":5000}`), nil)
+
+	//when
+	volume, err := resources.ResizeVolume(TEST_VOLUME_ID, VolumeResizeOptions{GbSize: 100, Iops: 1000})
+
+	//then
+	if assert.NoError(t, err) {
+		assert.Equal(t, Volume{Id: TEST_VOLUME_ID, GbSize: 100, Iops: 1000, DiskOfferingId: "custom"}, *volume)
+	}
+}
+
+func TestResizeVolumeValidatesAgainstNewDiskOffering(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","sizeInGb":50,"diskOfferingId":"small"}`), nil).Times(2)
+	mockServices.volumes.EXPECT().Execute(TEST_VOLUME_ID, "resize", []byte(`{"id":"`+TEST_VOLUME_ID+`","diskOfferingId":"large"}`), gomock.Any()).Return([]byte(`{}`), nil)
+	mockServices.diskOfferings.EXPECT().Get("large", gomock.Any()).Return([]byte(`{"id":"large","gbSize":200}`), nil)
+
+	//when
+	_, err := resources.ResizeVolume(TEST_VOLUME_ID, VolumeResizeOptions{DiskOfferingId: "large"})
+
+	//then
+	assert.NoError(t, err)
+}
+
+func TestResizeVolumeRefusesShrinkUnlessAllowed(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resources, mockServices := buildTestResources(ctrl)
+	mockServices.volumes.EXPECT().Get(TEST_VOLUME_ID, gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","sizeInGb":100,"diskOfferingId":"custom"}`), nil)
+	mockServices.diskOfferings.EXPECT().Get("custom", gomock.Any()).Return([]byte(`{"id":"custom","customSize":true}`), nil)
+
+	//when
+	volume, err := resources.ResizeVolume(TEST_VOLUME_ID, VolumeResizeOptions{GbSize: 50})
+
+	//then
+	assert.Nil(t, volume)
+	assert.Error(t, err)
+}
+
+func TestValidateVolumeResize(t *testing.T) {
+	volume := &Volume{Id: TEST_VOLUME_ID, GbSize: 100}
+	fixed := &DiskOffering{Name: "fixed", GbSize: 100}
+	custom := &DiskOffering{Name: "custom", CustomSize: true, CustomIops: true, MinIops: 500, MaxIops: 5000}
+
+	assert.NoError(t, validateVolumeResize(volume, custom, VolumeResizeOptions{GbSize: 50, AllowShrink: true}))
+	assert.NoError(t, validateVolumeResize(volume, custom, VolumeResizeOptions{Iops: 5000}))
+	assert.Error(t, validateVolumeResize(volume, fixed, VolumeResizeOptions{GbSize: 200}))
+	assert.Error(t, validateVolumeResize(volume, fixed, VolumeResizeOptions{Iops: 1000}))
+	assert.Error(t, validateVolumeResize(volume, custom, VolumeResizeOptions{Iops: 100}))
+	assert.Error(t, validateVolumeResize(volume, custom, VolumeResizeOptions{Iops: 6000}))
+	assert.Error(t, validateVolumeResize(volume, &DiskOffering{GbSize: 20}, VolumeResizeOptions{DiskOfferingId: "smaller"}))
+}
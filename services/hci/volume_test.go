@@ -347,3 +347,64 @@ func TestDeleteFailure(t *testing.T) {
 	// then
 	assert.Equal(t, mockError, err)
 }
+
+func TestUploadVolumeReturnsUploadedVolume(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	volumeService := VolumeApi{
+		entityService: mockEntityService,
+	}
+	expectedBody := []byte(`{"name":"migrated","zoneId":"zone","url":"https://images.example.com/disk.qcow2","format":"QCOW2","checksum":"{MD5}abc"}`)
+	mockEntityService.EXPECT().Create(expectedBody, map[string]string{"operation": VOLUME_UPLOAD_OPERATION}).Return([]byte(`{"id":"uploaded","name":"migrated"}`), nil)
+
+	// when
+	volume, err := volumeService.Upload(VolumeUpload{
+		Name:     "migrated",
+		ZoneId:   "zone",
+		Url:      "https://images.example.com/disk.qcow2",
+		Format:   VOLUME_FORMAT_QCOW2,
+		Checksum: "{MD5}abc",
+	})
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, Volume{Id: "uploaded", Name: "migrated"}, *volume)
+}
+
+func TestUploadVolumeFailsIfInvalid(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	volumeService := VolumeApi{
+		entityService: mockEntityService,
+	}
+
+	// when
+	_, urlErr := volumeService.Upload(VolumeUpload{Name: "migrated", Url: "ftp://images.example.com/disk.qcow2", Format: VOLUME_FORMAT_QCOW2})
+	_, formatErr := volumeService.Upload(VolumeUpload{Name: "migrated", Url: "https://images.example.com/disk.iso", Format: "ISO"})
+
+	// then
+	assert.Error(t, urlErr)
+	assert.Error(t, formatErr)
+}
+
+func TestExtractVolumeReturnsDownloadUrl(t *testing.T) {
+	// given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockEntityService := services_mocks.NewMockEntityService(ctrl)
+	volumeService := VolumeApi{
+		entityService: mockEntityService,
+	}
+	mockEntityService.EXPECT().Execute(TEST_VOLUME_ID, VOLUME_EXTRACT_OPERATION, gomock.Any(), gomock.Any()).Return([]byte(`{"id":"`+TEST_VOLUME_ID+`","url":"https://download.example.com/volume.qcow2","state":"DOWNLOAD_URL_CREATED"}`), nil)
+
+	// when
+	result, err := volumeService.Extract(TEST_VOLUME_ID)
+
+	// then
+	assert.Nil(t, err)
+	assert.Equal(t, ExtractResult{Id: TEST_VOLUME_ID, Url: "https://download.example.com/volume.qcow2", State: "DOWNLOAD_URL_CREATED"}, *result)
+}
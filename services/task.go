@@ -22,6 +22,8 @@ type Task struct {
 	Status  string
 	Created string
 	Result  []byte
+	// Percentage of completion, when reported by the operation
+	Progress int
 }
 
 type FailedTask Task
@@ -70,6 +72,9 @@ func (taskApi *TaskApi) Get(id string) (*Task, error) {
 	if val, ok := taskMap["result"]; ok {
 		task.Result = []byte(*val)
 	}
+	if val, ok := taskMap["progress"]; ok {
+		json.Unmarshal(*val, &task.Progress)
+	}
 	return &task, nil
 }
 
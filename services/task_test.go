@@ -46,6 +46,29 @@ func TestGetTaskReturnTaskIfSuccess(t *testing.T) {
 	assert.Equal(t, expectedTask, *task)
 }
 
+func TestGetTaskReturnProgressIfReported(t *testing.T) {
+	//given
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHciClient := api_mocks.NewMockApiClient(ctrl)
+
+	taskService := TaskApi{
+		apiClient: mockHciClient,
+	}
+
+	mockHciClient.EXPECT().Do(gomock.Any()).Return(&api.HciResponse{
+		StatusCode: 200,
+		Data:       []byte(`{"id":"` + TEST_TASK_ID + `", "status":"PENDING", "created":"2015-07-07", "progress":42}`),
+	}, nil)
+
+	//when
+	task, _ := taskService.Get(TEST_TASK_ID)
+
+	//then
+	assert.Equal(t, 42, task.Progress)
+}
+
 func TestGetTaskReturnErrorIfHasHciErrors(t *testing.T) {
 	//given
 	ctrl := gomock.NewController(t)